@@ -0,0 +1,91 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"strings"
+)
+
+const CardsPerBadugiHand = 4
+
+var InvalidBadugiHand = fmt.Errorf("badugi hand must contain exactly 4 cards")
+
+// ----- PUBLIC BADUGI EVALUATION API ----------------------------------------
+
+// Value of a hand for Badugi. A hand plays its largest subset of cards with
+// no two sharing a rank or a suit, so any 4-card badugi beats any 3-card
+// hand and so on. Hands playing the same number of cards are compared by
+// their highest card, then the next highest, etc, with aces low. The lower
+// the value, the better the hand, and two hands with the same value are tied.
+type BadugiValue uint32
+
+// Does this hand beat the other one?
+func (val BadugiValue) Beats(other BadugiValue) bool {
+	return val < other
+}
+
+// Report the number of cards this hand plays.
+func (val BadugiValue) Size() int {
+	return CardsPerBadugiHand - int(val>>13)
+}
+
+// Return the string representation of this hand, highest card first. (e.g.
+// "4-3-2-A", "K-7-2", etc)
+func (val BadugiValue) String() string {
+	rv := make([]string, 0, CardsPerBadugiHand)
+	for r := 12; r >= 0; r-- {
+		if val&(1<<uint(r)) != 0 {
+			rv = append(rv, rankStr[(r+12)%13])
+		}
+	}
+	return strings.Join(rv, "-")
+}
+
+// Determine the given hand's Badugi value. Returns InvalidBadugiHand unless
+// the hand contains exactly 4 cards, and InvalidCard if it holds the Joker.
+func EvaluateForBadugi(hand []Card) (BadugiValue, error) {
+	if len(hand) != CardsPerBadugiHand {
+		return 0, InvalidBadugiHand
+	}
+	if containsCard(hand, Joker) {
+		return 0, InvalidCard
+	}
+	return evalBadugi(hand), nil
+}
+
+// ----- BADUGI EVALUATION FUNCTIONS -----------------------------------------
+
+// Generate the Badugi value for a 4-card hand by trying every subset of the
+// cards and keeping the best one with no repeated ranks or suits. The number
+// of cards left out goes in the top bits, followed by the rank bitmask of the
+// cards played with aces rotated to the bottom.
+func evalBadugi(hand []Card) BadugiValue {
+	var best BadugiValue = 0xFFFFFFFF
+	for subset := 1; subset < 1<<CardsPerBadugiHand; subset++ {
+		var ranks, suits uint32
+		n, valid := 0, true
+		for i, card := range hand {
+			if subset&(1<<uint(i)) == 0 {
+				continue
+			}
+			rank := uint32(1) << uint(lowRank(card.Rank()))
+			suit := uint32(card) & 0xF000
+			if ranks&rank != 0 || suits&suit != 0 {
+				valid = false
+				break
+			}
+			ranks |= rank
+			suits |= suit
+			n++
+		}
+		if !valid {
+			continue
+		}
+		val := BadugiValue(CardsPerBadugiHand-n)<<13 | BadugiValue(ranks)
+		if val < best {
+			best = val
+		}
+	}
+	return best
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_can_detect_four_card_badugi(t *testing.T) {
+	val := badugi(t, makeHand([]string{"4s", "3h", "2d", "Ac"}))
+	if val.Size() != 4 || val.String() != "4-3-2-A" {
+		t.Fatalf("expected 4-3-2-A but was %s", val)
+	}
+	// any badugi beats any three-card hand
+	three := badugi(t, makeHand([]string{"3s", "2h", "Ad", "Ac"}))
+	king := badugi(t, makeHand([]string{"Ks", "Qh", "Jd", "Tc"}))
+	if !king.Beats(three) {
+		t.Fatalf("expected %s to beat %s", king, three)
+	}
+}
+
+func Test_paired_cards_do_not_play_in_badugi(t *testing.T) {
+	// the pair of deuces only plays one deuce
+	val := badugi(t, makeHand([]string{"2s", "2h", "5d", "7c"}))
+	if val.Size() != 3 || val.String() != "7-5-2" {
+		t.Fatalf("expected 7-5-2 but was %s", val)
+	}
+	// quads play a single card
+	val = badugi(t, makeHand([]string{"9s", "9h", "9d", "9c"}))
+	if val.Size() != 1 || val.String() != "9" {
+		t.Fatalf("expected 9 but was %s", val)
+	}
+}
+
+func Test_suited_cards_do_not_play_in_badugi(t *testing.T) {
+	// the higher of the two spades is discarded
+	val := badugi(t, makeHand([]string{"Ks", "3s", "5d", "7c"}))
+	if val.Size() != 3 || val.String() != "7-5-3" {
+		t.Fatalf("expected 7-5-3 but was %s", val)
+	}
+	// a flush plays only its lowest card
+	val = badugi(t, makeHand([]string{"Kh", "3h", "5h", "Ah"}))
+	if val.Size() != 1 || val.String() != "A" {
+		t.Fatalf("expected A but was %s", val)
+	}
+}
+
+func Test_picks_best_of_several_badugi_subsets(t *testing.T) {
+	// both 4-3-A and 4-2-A are valid three-card hands; the lower one plays
+	val := badugi(t, makeHand([]string{"Ac", "2d", "3d", "4h"}))
+	if val.Size() != 3 || val.String() != "4-2-A" {
+		t.Fatalf("expected 4-2-A but was %s", val)
+	}
+	// the ace of spades conflicts with both the ace of clubs and the deuce of
+	// spades, so it is better to throw it away and keep the other three
+	val = badugi(t, makeHand([]string{"As", "Ac", "2s", "3h"}))
+	if val.Size() != 3 || val.String() != "3-2-A" {
+		t.Fatalf("expected 3-2-A but was %s", val)
+	}
+}
+
+func Test_can_compare_badugis_of_same_size(t *testing.T) {
+	a := badugi(t, makeHand([]string{"8s", "4h", "3d", "2c"}))
+	b := badugi(t, makeHand([]string{"8c", "5h", "2d", "As"}))
+	// second highest card decides it, even though b holds the ace
+	if !a.Beats(b) {
+		t.Fatalf("expected %s to beat %s", a, b)
+	}
+	c := badugi(t, makeHand([]string{"8h", "5c", "2s", "Ad"}))
+	if b.Beats(c) || c.Beats(b) {
+		t.Fatalf("expected %s to tie %s", b, c)
+	}
+}
+
+func Test_rejects_badugi_hands_of_wrong_size(t *testing.T) {
+	hand := makeHand([]string{"8s", "4h", "3d", "2c", "Ac"})
+	if _, err := EvaluateForBadugi(hand); err != InvalidBadugiHand {
+		t.Fatalf("expected InvalidBadugiHand but was %v", err)
+	}
+}
+
+func Test_badugi_rejects_joker(t *testing.T) {
+	hand := makeHand([]string{"Jk", "4h", "3d", "2c"})
+	if _, err := EvaluateForBadugi(hand); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+}
+
+func badugi(t *testing.T, hand []Card) BadugiValue {
+	val, err := EvaluateForBadugi(hand)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	return val
+}
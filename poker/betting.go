@@ -0,0 +1,407 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+)
+
+type ActionType int
+
+// Betting actions
+const (
+	Fold = iota
+	Check
+	Call
+	Bet
+	Raise
+	AllIn
+)
+
+var actionNames = []string{"fold", "check", "call", "bet", "raise", "all-in"}
+
+// Reasons an action can be rejected, wrapped in an *ActionError.
+var (
+	RoundOver         = fmt.Errorf("betting round is over")
+	NotYourTurn       = fmt.Errorf("player is not next to act")
+	UnknownAction     = fmt.Errorf("unknown action")
+	CannotCheck       = fmt.Errorf("cannot check facing a bet")
+	NothingToCall     = fmt.Errorf("there is no bet to call")
+	AlreadyBet        = fmt.Errorf("there is already a bet; raise instead")
+	NothingToRaise    = fmt.Errorf("there is no bet to raise; bet instead")
+	BetTooSmall       = fmt.Errorf("bet or raise is below the minimum")
+	BetTooLarge       = fmt.Errorf("bet or raise is above the maximum")
+	NotEnoughChips    = fmt.Errorf("not enough chips")
+	RaiseCapReached   = fmt.Errorf("no more raises are allowed this round")
+	ActionNotReopened = fmt.Errorf("an incomplete raise does not reopen the betting")
+)
+
+// ----- PUBLIC BETTING API --------------------------------------------------
+
+// An action taken by a player. For Bet and Raise, Amount is the total the
+// player's bet for the round is made up to, not the amount added to it. (i.e.
+// a raise to 300 over a bet of 100) Other actions ignore Amount.
+type Action struct {
+	Type   ActionType
+	Amount uint32
+}
+
+// Return the action as it would be announced. (e.g. "raise to 300")
+func (action Action) String() string {
+	if action.Type < Fold || action.Type > AllIn {
+		return fmt.Sprintf("action %d", int(action.Type))
+	}
+	switch action.Type {
+	case Bet:
+		return fmt.Sprintf("bet %d", action.Amount)
+	case Raise:
+		return fmt.Sprintf("raise to %d", action.Amount)
+	}
+	return actionNames[action.Type]
+}
+
+// Error for an action that breaks the rules of the betting round. Reason is
+// one of the sentinel errors above. (i.e. CannotCheck, BetTooSmall, etc)
+type ActionError struct {
+	Seat   int
+	Action Action
+	Reason error
+}
+
+func (err *ActionError) Error() string {
+	return fmt.Sprintf("seat %d cannot %s: %v", err.Seat, err.Action, err.Reason)
+}
+
+func (err *ActionError) Unwrap() error {
+	return err.Reason
+}
+
+// State of a seat at the start of a betting round. Bet holds chips already
+// put in this round, such as blinds, and Stack the chips behind them. Acted
+// marks a forced bet that counts as the player's action, such as a stud
+// bring-in, so the player is not asked to act again unless someone raises.
+type Seat struct {
+	Stack  uint32
+	Bet    uint32
+	Folded bool
+	Acted  bool
+}
+
+// One round of betting. The round tracks whose turn it is, the bet to match
+// and the smallest legal raise, and which players have folded or are all in.
+// Players act through Act until Done reports the round is over.
+type BettingRound struct {
+	limit     GameLimit
+	betSize   uint32
+	maxRaises int
+	pot       uint32
+	stacks    []uint32
+	bets      []uint32
+	folded    []bool
+	// acted is set once a player has acted since the last full raise, and
+	// reopened is cleared when a player who has acted only faces incomplete
+	// raises since, and so may not raise again
+	acted      []bool
+	reopened   []bool
+	actor      int
+	currentBet uint32
+	raiseSize  uint32
+	raises     int
+	rejected   error
+}
+
+// Start a round of betting. betSize is the fixed bet in FixedLimit games, or
+// the minimum bet in PotLimit and NoLimit games. maxRaises caps the number of
+// raises after the opening bet, where blinds count as the opening bet, and is
+// unlimited if 0. pot holds the chips won in earlier rounds, which PotLimit
+// needs. Action starts with first, or the next player to their left who can
+// act.
+func NewBettingRound(limit GameLimit, betSize uint32, maxRaises int, pot uint32, seats []Seat, first int) *BettingRound {
+	cnt := len(seats)
+	round := &BettingRound{
+		limit:     limit,
+		betSize:   betSize,
+		maxRaises: maxRaises,
+		pot:       pot,
+		stacks:    make([]uint32, cnt),
+		bets:      make([]uint32, cnt),
+		folded:    make([]bool, cnt),
+		acted:     make([]bool, cnt),
+		reopened:  make([]bool, cnt),
+		raiseSize: betSize,
+	}
+	for i, seat := range seats {
+		round.stacks[i] = seat.Stack
+		round.bets[i] = seat.Bet
+		round.folded[i] = seat.Folded
+		round.acted[i] = seat.Acted
+		round.reopened[i] = true
+		if seat.Bet > round.currentBet {
+			round.currentBet = seat.Bet
+		}
+	}
+	round.actor = round.nextActor(first - 1)
+	return round
+}
+
+// Report the seat due to act, or -1 if the round is over.
+func (round *BettingRound) Actor() int {
+	return round.actor
+}
+
+// Is this round of betting over?
+func (round *BettingRound) Done() bool {
+	return round.actor < 0
+}
+
+// Report the bet every player still in the hand must match.
+func (round *BettingRound) CurrentBet() uint32 {
+	return round.currentBet
+}
+
+// Report how many chips the given seat must add to call.
+func (round *BettingRound) ToCall(seat int) uint32 {
+	owed := round.currentBet - round.bets[seat]
+	if round.currentBet < round.bets[seat] {
+		owed = 0
+	}
+	if owed > round.stacks[seat] {
+		return round.stacks[seat]
+	}
+	return owed
+}
+
+// Report the smallest total the given seat may bet or raise to, not counting
+// going all in for less. Facing a bet smaller than the bet size, such as a
+// bring-in, this is the bet size itself, which completes the bet.
+func (round *BettingRound) MinRaiseTo(seat int) uint32 {
+	if round.currentBet < round.betSize {
+		return round.betSize
+	}
+	return round.currentBet + round.raiseSize
+}
+
+// Report the largest total the given seat may bet or raise to, not counting
+// going all in.
+func (round *BettingRound) MaxRaiseTo(seat int) uint32 {
+	switch round.limit {
+	case FixedLimit:
+		if round.currentBet < round.betSize {
+			return round.betSize
+		}
+		return round.currentBet + round.betSize
+	case PotLimit:
+		// call, then raise by the size of the pot after the call
+		return round.currentBet + round.Pot() + round.currentBet - round.bets[seat]
+	}
+	return round.bets[seat] + round.stacks[seat]
+}
+
+// Report the chips the given seat has put in this round.
+func (round *BettingRound) BetOf(seat int) uint32 {
+	return round.bets[seat]
+}
+
+// Report the chips the given seat has behind.
+func (round *BettingRound) StackOf(seat int) uint32 {
+	return round.stacks[seat]
+}
+
+// Has the given seat folded?
+func (round *BettingRound) Folded(seat int) bool {
+	return round.folded[seat]
+}
+
+// Is the given seat all in?
+func (round *BettingRound) AllIn(seat int) bool {
+	return !round.folded[seat] && round.stacks[seat] == 0
+}
+
+// Report the chips won in earlier rounds plus everything bet in this one.
+func (round *BettingRound) Pot() uint32 {
+	pot := round.pot
+	for _, bet := range round.bets {
+		pot += bet
+	}
+	return pot
+}
+
+// Take the given action for the seat due to act. Returns an *ActionError,
+// and leaves the round as it was, if the action is not legal.
+func (round *BettingRound) Act(seat int, action Action) error {
+	if err := round.act(seat, action); err != nil {
+		err := &ActionError{Seat: seat, Action: action, Reason: err}
+		if seat == round.actor {
+			round.rejected = err
+		}
+		return err
+	}
+	round.rejected = nil
+	round.acted[seat] = true
+	round.actor = round.nextActor(seat)
+	return nil
+}
+
+// Report the *ActionError for the last action the seat due to act tried and
+// had rejected, or nil if it has not had one rejected since the last action
+// was taken. A player asked to act again can use this to see what was wrong.
+func (round *BettingRound) Rejected() error {
+	return round.rejected
+}
+
+// ----- BETTING FUNCTIONS ---------------------------------------------------
+
+// Validate the action and apply it to the round.
+func (round *BettingRound) act(seat int, action Action) error {
+	if round.Done() {
+		return RoundOver
+	}
+	if seat != round.actor {
+		return NotYourTurn
+	}
+	owed := round.ToCall(seat)
+	switch action.Type {
+	case Fold:
+		round.folded[seat] = true
+		return nil
+	case Check:
+		if owed > 0 {
+			return CannotCheck
+		}
+		return nil
+	case Call:
+		if owed == 0 {
+			return NothingToCall
+		}
+		round.put(seat, owed)
+		return nil
+	case Bet:
+		if round.currentBet > 0 {
+			return AlreadyBet
+		}
+		return round.raiseTo(seat, action.Amount)
+	case Raise:
+		if round.currentBet == 0 {
+			return NothingToRaise
+		}
+		return round.raiseTo(seat, action.Amount)
+	case AllIn:
+		total := round.bets[seat] + round.stacks[seat]
+		if total <= round.currentBet {
+			round.put(seat, round.stacks[seat])
+			return nil
+		}
+		if err := round.canRaise(seat); err != nil {
+			return err
+		}
+		if total > round.MaxRaiseTo(seat) {
+			return BetTooLarge
+		}
+		round.raise(seat, total)
+		return nil
+	}
+	return UnknownAction
+}
+
+// Check whether the given seat may put in a bet or raise at all.
+func (round *BettingRound) canRaise(seat int) error {
+	if !round.reopened[seat] {
+		return ActionNotReopened
+	}
+	if round.maxRaises > 0 && round.currentBet > 0 && round.raises >= round.maxRaises {
+		return RaiseCapReached
+	}
+	return nil
+}
+
+// Validate a bet or raise to the given total, then make it.
+func (round *BettingRound) raiseTo(seat int, total uint32) error {
+	if err := round.canRaise(seat); err != nil {
+		return err
+	}
+	if total > round.bets[seat]+round.stacks[seat] {
+		return NotEnoughChips
+	}
+	if total < round.MinRaiseTo(seat) {
+		if total == round.bets[seat]+round.stacks[seat] && total > round.currentBet {
+			// all in for less than a full raise
+			round.raise(seat, total)
+			return nil
+		}
+		return BetTooSmall
+	}
+	if total > round.MaxRaiseTo(seat) {
+		return BetTooLarge
+	}
+	round.raise(seat, total)
+	return nil
+}
+
+// Raise the bet to the given total. An opening bet, a completed bet or a full
+// raise reopens the betting for everyone; an incomplete raise only obliges
+// the others to call the extra. Each incomplete raise is judged on its own,
+// even if several of them add up to a full raise.
+func (round *BettingRound) raise(seat int, total uint32) {
+	increase := total - round.currentBet
+	full := round.currentBet == 0 || total >= round.MinRaiseTo(seat)
+	round.put(seat, total-round.bets[seat])
+	for i := range round.acted {
+		if i == seat {
+			continue
+		}
+		if full {
+			round.reopened[i] = true
+		} else if round.acted[i] {
+			round.reopened[i] = false
+		}
+		round.acted[i] = false
+	}
+	if full {
+		if round.currentBet >= round.betSize {
+			round.raises++
+		}
+		round.raiseSize = increase
+		if increase < round.betSize {
+			round.raiseSize = round.betSize
+		}
+	}
+	round.currentBet = total
+}
+
+// Move chips from the seat's stack into its bet.
+func (round *BettingRound) put(seat int, amount uint32) {
+	round.stacks[seat] -= amount
+	round.bets[seat] += amount
+}
+
+// Find the next seat after the given one that still has to act, or -1 if the
+// round is over.
+func (round *BettingRound) nextActor(after int) int {
+	cnt := len(round.stacks)
+	live, active := 0, 0
+	for i := 0; i < cnt; i++ {
+		if !round.folded[i] {
+			live++
+			if round.stacks[i] > 0 {
+				active++
+			}
+		}
+	}
+	if live <= 1 || active == 0 {
+		return -1
+	}
+	for j := 1; j <= cnt; j++ {
+		i := ((after+j)%cnt + cnt) % cnt
+		if round.folded[i] || round.stacks[i] == 0 {
+			continue
+		}
+		if round.bets[i] < round.currentBet {
+			return i
+		}
+		// nobody left to bet against, so there is nothing to decide
+		if !round.acted[i] && active > 1 {
+			return i
+		}
+	}
+	return -1
+}
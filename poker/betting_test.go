@@ -0,0 +1,200 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_can_play_no_limit_round(t *testing.T) {
+	// blinds of 50 and 100 in seats 1 and 2, action on seat 3
+	round := NewBettingRound(NoLimit, 100, 0, 0, []Seat{{Stack: 1000}, {Stack: 950, Bet: 50}, {Stack: 900, Bet: 100}, {Stack: 1000}}, 3)
+	act(t, round, 3, Action{Type: Raise, Amount: 300})
+	if round.MinRaiseTo(0) != 500 {
+		t.Fatalf("expected minimum raise to 500 but was %d", round.MinRaiseTo(0))
+	}
+	act(t, round, 0, Action{Type: Fold})
+	act(t, round, 1, Action{Type: Call})
+	act(t, round, 2, Action{Type: Raise, Amount: 800})
+	act(t, round, 3, Action{Type: Call})
+	act(t, round, 1, Action{Type: Fold})
+	if !round.Done() {
+		t.Fatalf("expected round to be over but seat %d is to act", round.Actor())
+	}
+	if round.Pot() != 1900 || round.StackOf(3) != 200 || round.BetOf(1) != 300 {
+		t.Fatalf("expected pot of 1900 but was %d", round.Pot())
+	}
+}
+
+func Test_big_blind_gets_option(t *testing.T) {
+	round := NewBettingRound(NoLimit, 100, 0, 0, []Seat{{Stack: 1000}, {Stack: 950, Bet: 50}, {Stack: 900, Bet: 100}}, 0)
+	act(t, round, 0, Action{Type: Call})
+	act(t, round, 1, Action{Type: Call})
+	if round.Actor() != 2 {
+		t.Fatalf("expected big blind to act but was seat %d", round.Actor())
+	}
+	act(t, round, 2, Action{Type: Check})
+	if !round.Done() {
+		t.Fatalf("expected round to be over")
+	}
+}
+
+func Test_rejects_illegal_actions(t *testing.T) {
+	round := NewBettingRound(NoLimit, 100, 0, 0, []Seat{{Stack: 1000}, {Stack: 950, Bet: 50}, {Stack: 900, Bet: 100}, {Stack: 1000}}, 3)
+	for _, c := range []struct {
+		seat   int
+		action Action
+		reason error
+	}{
+		{0, Action{Type: Call}, NotYourTurn},
+		{3, Action{Type: Check}, CannotCheck},
+		{3, Action{Type: Bet, Amount: 300}, AlreadyBet},
+		{3, Action{Type: Raise, Amount: 150}, BetTooSmall},
+		{3, Action{Type: Raise, Amount: 1500}, NotEnoughChips},
+		{3, Action{Type: 42}, UnknownAction},
+	} {
+		err := round.Act(c.seat, c.action)
+		var actionErr *ActionError
+		if !errors.As(err, &actionErr) || !errors.Is(err, c.reason) {
+			t.Fatalf("expected %v for %s but was %v", c.reason, c.action, err)
+		}
+	}
+	if round.Actor() != 3 || round.Pot() != 150 {
+		t.Fatalf("expected rejected actions to leave the round alone")
+	}
+	if err := round.Rejected(); !errors.Is(err, UnknownAction) {
+		t.Fatalf("expected UnknownAction to be reported but was %v", err)
+	}
+	act(t, round, 3, Action{Type: Call})
+	if err := round.Rejected(); err != nil {
+		t.Fatalf("expected nothing to be reported but was %v", err)
+	}
+	// postflop there is nothing to call or raise
+	round = NewBettingRound(NoLimit, 100, 0, 300, []Seat{{Stack: 1000}, {Stack: 1000}, {Stack: 1000}}, 0)
+	for action, reason := range map[ActionType]error{Call: NothingToCall, Raise: NothingToRaise} {
+		if err := round.Act(0, Action{Type: action, Amount: 200}); !errors.Is(err, reason) {
+			t.Fatalf("expected %v but was %v", reason, err)
+		}
+	}
+	act(t, round, 0, Action{Type: Check})
+	act(t, round, 1, Action{Type: Check})
+	act(t, round, 2, Action{Type: Check})
+	if err := round.Act(0, Action{Type: Check}); !errors.Is(err, RoundOver) {
+		t.Fatalf("expected RoundOver but was %v", err)
+	}
+}
+
+func Test_fixed_limit_bets_and_cap(t *testing.T) {
+	round := NewBettingRound(FixedLimit, 100, 3, 0, []Seat{{Stack: 1000}, {Stack: 1000}}, 0)
+	if err := round.Act(0, Action{Type: Bet, Amount: 200}); !errors.Is(err, BetTooLarge) {
+		t.Fatalf("expected BetTooLarge but was %v", err)
+	}
+	act(t, round, 0, Action{Type: Bet, Amount: 100})
+	if err := round.Act(1, Action{Type: Raise, Amount: 300}); !errors.Is(err, BetTooLarge) {
+		t.Fatalf("expected BetTooLarge but was %v", err)
+	}
+	act(t, round, 1, Action{Type: Raise, Amount: 200})
+	act(t, round, 0, Action{Type: Raise, Amount: 300})
+	act(t, round, 1, Action{Type: Raise, Amount: 400})
+	// bet and three raises
+	if err := round.Act(0, Action{Type: Raise, Amount: 500}); !errors.Is(err, RaiseCapReached) {
+		t.Fatalf("expected RaiseCapReached but was %v", err)
+	}
+	act(t, round, 0, Action{Type: Call})
+	if !round.Done() || round.Pot() != 800 {
+		t.Fatalf("expected round over with 800 in the pot but was %d", round.Pot())
+	}
+}
+
+func Test_can_complete_bring_in(t *testing.T) {
+	// a bring-in of 25 with antes of 10 in the pot
+	round := NewBettingRound(FixedLimit, 100, 3, 30, []Seat{{Stack: 965, Bet: 25}, {Stack: 990}, {Stack: 990}}, 1)
+	if round.MinRaiseTo(1) != 100 || round.MaxRaiseTo(1) != 100 {
+		t.Fatalf("expected completion to 100 but was %d", round.MinRaiseTo(1))
+	}
+	act(t, round, 1, Action{Type: Raise, Amount: 100})
+	if round.MinRaiseTo(2) != 200 {
+		t.Fatalf("expected minimum raise to 200 but was %d", round.MinRaiseTo(2))
+	}
+	act(t, round, 2, Action{Type: Raise, Amount: 200})
+	act(t, round, 0, Action{Type: Raise, Amount: 300})
+	act(t, round, 1, Action{Type: Raise, Amount: 400})
+	// completion and three raises
+	if err := round.Act(2, Action{Type: Raise, Amount: 500}); !errors.Is(err, RaiseCapReached) {
+		t.Fatalf("expected RaiseCapReached but was %v", err)
+	}
+}
+
+func Test_called_bring_in_ends_round(t *testing.T) {
+	round := NewBettingRound(FixedLimit, 100, 3, 20, []Seat{{Stack: 965, Bet: 25, Acted: true}, {Stack: 990}}, 1)
+	act(t, round, 1, Action{Type: Call})
+	if !round.Done() {
+		t.Fatalf("expected round over but seat %d is due to act", round.Actor())
+	}
+}
+
+func Test_pot_limit_maximum(t *testing.T) {
+	// blinds of 50 and 100: a pot-sized raise from seat 2 is to 350
+	round := NewBettingRound(PotLimit, 100, 0, 0, []Seat{{Stack: 1950, Bet: 50}, {Stack: 900, Bet: 100}, {Stack: 1000}}, 2)
+	if round.MaxRaiseTo(2) != 350 {
+		t.Fatalf("expected maximum raise to 350 but was %d", round.MaxRaiseTo(2))
+	}
+	if err := round.Act(2, Action{Type: Raise, Amount: 400}); !errors.Is(err, BetTooLarge) {
+		t.Fatalf("expected BetTooLarge but was %v", err)
+	}
+	act(t, round, 2, Action{Type: Raise, Amount: 350})
+	// small blind calls 300 more into 800, then raises 800
+	if round.MaxRaiseTo(0) != 1150 {
+		t.Fatalf("expected maximum raise to 1150 but was %d", round.MaxRaiseTo(0))
+	}
+	if err := round.Act(0, Action{Type: AllIn}); !errors.Is(err, BetTooLarge) {
+		t.Fatalf("expected BetTooLarge but was %v", err)
+	}
+}
+
+func Test_incomplete_all_in_does_not_reopen_betting(t *testing.T) {
+	round := NewBettingRound(NoLimit, 100, 0, 0, []Seat{{Stack: 1000}, {Stack: 1000}, {Stack: 150}}, 0)
+	act(t, round, 0, Action{Type: Bet, Amount: 100})
+	act(t, round, 1, Action{Type: Call})
+	// all in for 50 more than the bet, less than a full raise
+	act(t, round, 2, Action{Type: AllIn})
+	if round.CurrentBet() != 150 || !round.AllIn(2) {
+		t.Fatalf("expected bet of 150 but was %d", round.CurrentBet())
+	}
+	if err := round.Act(0, Action{Type: Raise, Amount: 400}); !errors.Is(err, ActionNotReopened) {
+		t.Fatalf("expected ActionNotReopened but was %v", err)
+	}
+	act(t, round, 0, Action{Type: Call})
+	act(t, round, 1, Action{Type: Call})
+	if !round.Done() {
+		t.Fatalf("expected round to be over but seat %d is to act", round.Actor())
+	}
+
+	// a full raise all in does reopen it
+	round = NewBettingRound(NoLimit, 100, 0, 0, []Seat{{Stack: 1000}, {Stack: 1000}, {Stack: 200}}, 0)
+	act(t, round, 0, Action{Type: Bet, Amount: 100})
+	act(t, round, 1, Action{Type: Call})
+	act(t, round, 2, Action{Type: AllIn})
+	act(t, round, 0, Action{Type: Raise, Amount: 500})
+}
+
+func Test_round_skips_players_who_cannot_act(t *testing.T) {
+	// everyone but one player is all in, so there is nobody to bet against
+	round := NewBettingRound(NoLimit, 100, 0, 2000, []Seat{{Stack: 0}, {Stack: 500}, {Folded: true, Stack: 800}}, 0)
+	if !round.Done() {
+		t.Fatalf("expected round to be over but seat %d is to act", round.Actor())
+	}
+	round = NewBettingRound(NoLimit, 100, 0, 0, []Seat{{Stack: 100}, {Stack: 500}}, 0)
+	act(t, round, 0, Action{Type: AllIn})
+	act(t, round, 1, Action{Type: Call})
+	if !round.Done() || round.StackOf(1) != 400 {
+		t.Fatalf("expected round over after call")
+	}
+}
+
+func act(t *testing.T, round *BettingRound, seat int, action Action) {
+	if err := round.Act(seat, action); err != nil {
+		t.Fatalf("cannot act: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"sort"
+)
+
+// Suits in the order used to index a SuitPermutation.
+var suitOrder = []int{Club, Diamond, Heart, Spade}
+
+// ----- PUBLIC SUIT ISOMORPHISM API -----------------------------------------
+
+// Relabelling of the four suits. The entry for each suit, indexed club,
+// diamond, heart, spade, is the suit it becomes. (e.g. Heart for a
+// permutation that turns clubs into hearts)
+type SuitPermutation [4]int
+
+// Permutation that leaves every suit as it is.
+var IdentitySuits = SuitPermutation{Club, Diamond, Heart, Spade}
+
+// Return the card this permutation turns the given card into.
+func (perm SuitPermutation) Apply(card Card) Card {
+	return NewCard(card.Rank(), perm[suitIndex(card.Suit())])
+}
+
+// Return a copy of the given cards with this permutation applied to each.
+func (perm SuitPermutation) ApplyHand(hand []Card) []Card {
+	rv := make([]Card, len(hand))
+	for i, card := range hand {
+		rv[i] = perm.Apply(card)
+	}
+	return rv
+}
+
+// Return the permutation that undoes this one.
+func (perm SuitPermutation) Inverse() SuitPermutation {
+	var inv SuitPermutation
+	for i, suit := range perm {
+		inv[suitIndex(suit)] = suitOrder[i]
+	}
+	return inv
+}
+
+// Map a hand and board to the canonical representative of every hand and
+// board that differ from them only by relabelling suits. (e.g. AhKh on
+// 2h7c9d and AsKs on 2s7d9c have the same canonical form) The order of the
+// cards within the hand or the board does not matter. Returns the canonical
+// hand and board, each sorted from highest card down, along with the
+// permutation that turns the given cards into them.
+func Canonicalize(hand, board []Card) ([]Card, []Card, SuitPermutation) {
+	var best []Card
+	var bestPerm SuitPermutation
+	perm := IdentitySuits
+	permute(perm[:], 0, func() {
+		key := append(sortedDesc(perm.ApplyHand(hand)), sortedDesc(perm.ApplyHand(board))...)
+		if best == nil || greaterKey(key, best) {
+			best, bestPerm = key, perm
+		}
+	})
+	return best[:len(hand):len(hand)], best[len(hand):], bestPerm
+}
+
+// ----- SUIT ISOMORPHISM FUNCTIONS ------------------------------------------
+
+// Report the position of the given suit in suitOrder.
+func suitIndex(suit int) int {
+	switch suit {
+	case Club:
+		return 0
+	case Diamond:
+		return 1
+	case Heart:
+		return 2
+	}
+	return 3
+}
+
+// Call visit once for each ordering of suits[k:], rearranging it in place.
+func permute(suits []int, k int, visit func()) {
+	if k == len(suits) {
+		visit()
+		return
+	}
+	for i := k; i < len(suits); i++ {
+		suits[k], suits[i] = suits[i], suits[k]
+		permute(suits, k+1, visit)
+		suits[k], suits[i] = suits[i], suits[k]
+	}
+}
+
+// Sort cards in place from highest to lowest, by rank and then by suit, and
+// return them.
+func sortedDesc(cards []Card) []Card {
+	sort.Slice(cards, func(i, j int) bool {
+		return cards[i] > cards[j]
+	})
+	return cards
+}
+
+// Does a come after b when comparing them card by card?
+func greaterKey(a, b []Card) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return false
+}
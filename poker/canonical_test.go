@@ -0,0 +1,67 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_starting_hands_collapse_to_169_classes(t *testing.T) {
+	deck := NewPokerDeck().cards
+	classes := make(map[[2]Card]int)
+	for i := 0; i < len(deck); i++ {
+		for j := i + 1; j < len(deck); j++ {
+			hand, _, _ := Canonicalize([]Card{deck[i], deck[j]}, nil)
+			classes[[2]Card{hand[0], hand[1]}]++
+		}
+	}
+	if len(classes) != 169 {
+		t.Fatalf("expected 169 classes but was %d", len(classes))
+	}
+	// 13 pairs of 6 combos, 78 suited hands of 4 and 78 offsuit hands of 12
+	sizes := make(map[int]int)
+	for _, n := range classes {
+		sizes[n]++
+	}
+	if sizes[6] != 13 || sizes[4] != 78 || sizes[12] != 78 {
+		t.Fatalf("expected 13 pairs, 78 suited and 78 offsuit but was %v", sizes)
+	}
+}
+
+func Test_flops_collapse_to_1755_classes(t *testing.T) {
+	deck := NewPokerDeck().cards
+	classes := make(map[[3]Card]bool)
+	for i := 0; i < len(deck); i++ {
+		for j := i + 1; j < len(deck); j++ {
+			for k := j + 1; k < len(deck); k++ {
+				_, board, _ := Canonicalize(nil, []Card{deck[i], deck[j], deck[k]})
+				classes[[3]Card{board[0], board[1], board[2]}] = true
+			}
+		}
+	}
+	if len(classes) != 1755 {
+		t.Fatalf("expected 1755 classes but was %d", len(classes))
+	}
+}
+
+func Test_isomorphic_hands_share_canonical_form(t *testing.T) {
+	h1, b1, p1 := Canonicalize(makeHand([]string{"Ah", "Kh"}), makeHand([]string{"2h", "7c", "9d"}))
+	h2, b2, p2 := Canonicalize(makeHand([]string{"Ks", "As"}), makeHand([]string{"9c", "2s", "7d"}))
+	if PrintHand(h1) != PrintHand(h2) || PrintHand(b1) != PrintHand(b2) {
+		t.Fatalf("expected %s on %s but was %s on %s", PrintHand(h1), PrintHand(b1), PrintHand(h2), PrintHand(b2))
+	}
+	// the permutations map each input onto the canonical form and back
+	hand := makeHand([]string{"Ah", "Kh"})
+	if PrintHand(sortedDesc(p1.ApplyHand(hand))) != PrintHand(h1) {
+		t.Fatalf("expected %s but was %s", PrintHand(h1), PrintHand(p1.ApplyHand(hand)))
+	}
+	back := p2.Inverse().ApplyHand(b2)
+	if PrintHand(sortedDesc(back)) != "(9c,7d,2s)" {
+		t.Fatalf("expected (9c,7d,2s) but was %s", PrintHand(back))
+	}
+	// a different board texture is not isomorphic
+	_, b3, _ := Canonicalize(makeHand([]string{"Ah", "Kh"}), makeHand([]string{"2c", "7c", "9d"}))
+	if PrintHand(b3) == PrintHand(b1) {
+		t.Fatalf("expected %s to differ from %s", PrintHand(b3), PrintHand(b1))
+	}
+}
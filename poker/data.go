@@ -870,6 +870,15 @@ var perm7 = [21][5]uint32{
 	{2, 3, 4, 5, 6},
 }
 
+var perm6 = [6][5]uint32{
+	{0, 1, 2, 3, 4},
+	{0, 1, 2, 3, 5},
+	{0, 1, 2, 4, 5},
+	{0, 1, 3, 4, 5},
+	{0, 2, 3, 4, 5},
+	{1, 2, 3, 4, 5},
+}
+
 var hash_adjust = []uint32{
 	0, 5628, 7017, 1298, 2918, 2442, 8070, 6383, 6383, 7425, 2442, 5628, 8044, 7425, 3155, 6383,
 	2918, 7452, 1533, 6849, 5586, 7452, 7452, 1533, 2209, 6029, 2794, 3509, 7992, 7733, 7452, 131,
@@ -0,0 +1,146 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"sort"
+	"strings"
+)
+
+var rankNames = []string{"Deuce", "Trey", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen", "King", "Ace"}
+var rankPlurals = []string{"Deuces", "Treys", "Fours", "Fives", "Sixes", "Sevens", "Eights", "Nines", "Tens", "Jacks", "Queens", "Kings", "Aces"}
+
+var categoryNames = map[int]string{
+	StraightFlush: "Straight Flush",
+	FourOfAKind:   "Four of a Kind",
+	FullHouse:     "Full House",
+	Flush:         "Flush",
+	Straight:      "Straight",
+	ThreeOfAKind:  "Three of a Kind",
+	TwoPair:       "Two Pair",
+	OnePair:       "One Pair",
+	HighCard:      "High Card",
+}
+
+// ----- PUBLIC HAND DESCRIPTION API -----------------------------------------
+
+// Human-readable description of a hand for high. Category names the ranking
+// (i.e. "Two Pair"), Detail says what it is made of (i.e. "Kings and Fives")
+// and Kickers lists any cards that play alongside it (i.e. "with an Ace
+// kicker"). Cards holds the five cards the hand plays, most important first.
+type HandDescription struct {
+	Category string
+	Detail   string
+	Kickers  string
+	Cards    []Card
+}
+
+// Return the full description. (e.g. "Two Pair, Kings and Fives with an Ace
+// kicker")
+func (desc HandDescription) String() string {
+	s := desc.Category + ", " + desc.Detail
+	if desc.Kickers != "" {
+		s += " " + desc.Kickers
+	}
+	return s
+}
+
+// Return the full description followed by the cards played. (e.g. "Two
+// Pair, Kings and Fives with an Ace kicker (Kh,Kd,5s,5c,Ah)")
+func (desc HandDescription) WithCards() string {
+	return desc.String() + " " + PrintHand(desc.Cards)
+}
+
+// Describe the best five card hand for high that can be made from the given
+// hand. Returns InvalidHandSize unless the hand contains 5, 6 or 7 cards.
+func Describe(hand []Card) (HandDescription, error) {
+	eval, err := Evaluate(hand)
+	if err != nil {
+		return HandDescription{}, err
+	}
+	return eval.Describe(), nil
+}
+
+// Describe the hand this evaluation was made for.
+func (eval Evaluation) Describe() HandDescription {
+	cards := eval.Cards
+	rank := eval.Value.Rank()
+	desc := HandDescription{Category: categoryNames[rank], Cards: cards}
+	switch rank {
+	case StraightFlush:
+		if cards[0].Rank() == Ace {
+			desc.Category = "Royal Flush"
+			desc.Detail = "Ten to Ace"
+		} else {
+			desc.Detail = rankNames[cards[0].Rank()] + " high"
+		}
+	case Straight:
+		desc.Detail = rankNames[cards[0].Rank()] + " high"
+	case Flush, HighCard:
+		desc.Detail = rankNames[cards[0].Rank()] + " high"
+		desc.Kickers = kickerText(cards[1:])
+	case FourOfAKind:
+		desc.Detail = rankPlurals[cards[0].Rank()]
+		desc.Kickers = kickerText(cards[4:])
+	case FullHouse:
+		desc.Detail = rankPlurals[cards[0].Rank()] + " full of " + rankPlurals[cards[3].Rank()]
+	case ThreeOfAKind:
+		desc.Detail = rankPlurals[cards[0].Rank()]
+		desc.Kickers = kickerText(cards[3:])
+	case TwoPair:
+		desc.Detail = rankPlurals[cards[0].Rank()] + " and " + rankPlurals[cards[2].Rank()]
+		desc.Kickers = kickerText(cards[4:])
+	case OnePair:
+		desc.Detail = rankPlurals[cards[0].Rank()]
+		desc.Kickers = kickerText(cards[2:])
+	}
+	return desc
+}
+
+// ----- HAND DESCRIPTION FUNCTIONS ------------------------------------------
+
+// Order five cards so the ones that matter most come first: larger groups of
+// a rank before smaller ones, then higher ranks before lower ones. Aces in a
+// five-high straight are played low, so they go last.
+func orderBySignificance(val HandValue, five []Card) []Card {
+	cards := make([]Card, len(five))
+	copy(cards, five)
+	var counts [13]int
+	for _, card := range cards {
+		counts[card.Rank()]++
+	}
+	rank := val.Rank()
+	wheel := (rank == Straight || rank == StraightFlush) && counts[Ace] == 1 && counts[Five] == 1
+	sort.Slice(cards, func(i, j int) bool {
+		ri, rj := cards[i].Rank(), cards[j].Rank()
+		if counts[ri] != counts[rj] {
+			return counts[ri] > counts[rj]
+		}
+		if wheel {
+			ri, rj = (ri+1)%13, (rj+1)%13
+		}
+		if ri != rj {
+			return ri > rj
+		}
+		return cards[i].Suit() > cards[j].Suit()
+	})
+	return cards
+}
+
+// Describe the given kicker cards. (e.g. "with an Ace kicker", "with Jack,
+// Ten, Four kickers")
+func kickerText(kickers []Card) string {
+	if len(kickers) == 1 {
+		name := rankNames[kickers[0].Rank()]
+		article := "a"
+		if name == "Ace" || name == "Eight" {
+			article = "an"
+		}
+		return "with " + article + " " + name + " kicker"
+	}
+	names := make([]string, len(kickers))
+	for i, card := range kickers {
+		names[i] = rankNames[card.Rank()]
+	}
+	return "with " + strings.Join(names, ", ") + " kickers"
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_can_describe_two_pair(t *testing.T) {
+	hand := makeHand([]string{"Ks", "5d", "Kh", "2c", "5c", "Ah", "3d"})
+	expectDescription(t, hand, "Two Pair, Kings and Fives with an Ace kicker (Kh,Ks,5c,5d,Ah)")
+}
+
+func Test_can_describe_full_house(t *testing.T) {
+	hand := makeHand([]string{"Ts", "Tc", "8h", "Ks", "Td", "Kd", "Kh"})
+	expectDescription(t, hand, "Full House, Kings full of Tens (Kd,Kh,Ks,Tc,Ts)")
+}
+
+func Test_can_describe_wheel(t *testing.T) {
+	hand := makeHand([]string{"Ad", "Ac", "2c", "4c", "Kd", "3c", "5c"})
+	expectDescription(t, hand, "Straight Flush, Five high (5c,4c,3c,2c,Ac)")
+	hand = makeHand([]string{"Ad", "2s", "4c", "3h", "5c"})
+	expectDescription(t, hand, "Straight, Five high (5c,4c,3h,2s,Ad)")
+}
+
+func Test_can_describe_royal_flush(t *testing.T) {
+	hand := makeHand([]string{"Td", "Kd", "7s", "Jd", "Ad", "3c", "Qd"})
+	expectDescription(t, hand, "Royal Flush, Ten to Ace (Ad,Kd,Qd,Jd,Td)")
+}
+
+func Test_can_describe_high_card(t *testing.T) {
+	hand := makeHand([]string{"Ts", "9s", "8h", "Ks", "4s", "3d", "Jh"})
+	expectDescription(t, hand, "High Card, King high with Jack, Ten, Nine, Eight kickers (Ks,Jh,Ts,9s,8h)")
+}
+
+func expectDescription(t *testing.T, hand []Card, expected string) {
+	desc, err := Describe(hand)
+	if err != nil {
+		t.Fatalf("cannot describe %s: %v", PrintHand(hand), err)
+	}
+	if desc.WithCards() != expected {
+		t.Fatalf("expected %q but was %q", expected, desc.WithCards())
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+// ----- PUBLIC DRAW GAME API ------------------------------------------------
+
+// Record for draw games. (5Draw, 2-7Lo, 2-7TripleDrawLo and Badugi)
+type DrawGame struct {
+	table
+	game GameType
+}
+
+// Create a new draw game instance. Returns TableFull if there are more than
+// MaxSeats players.
+func NewDrawGame(players []Player, deck Deck, game GameType, limit GameLimit, blinds Blinds, maxRaises int) (*DrawGame, error) {
+	if len(players) > MaxSeats {
+		return nil, TableFull
+	}
+	return &DrawGame{
+		table: newTable(players, deck, limit, blinds, maxRaises),
+		game:  game,
+	}, nil
+}
+
+// 5Draw/2-7Lo/2-7TripleDrawLo/Badugi:
+//  1. Post ante and blinds
+//  2. Deal 4 (Badugi) or 5 (all others) private cards
+//  3. For each round (1 for 5Draw/2-7Lo or 3 for Triple Draw/Badugi):
+//     a. Round of betting
+//     b. Draw/discard
+//  4. Round of betting
+//  5. Showdown
+//
+// Limit games bet the lower limit in the first half of the rounds of betting
+// and the higher limit in the rest.
+func (game *DrawGame) Play() {
+	if !game.init() {
+		return
+	}
+	game.DealPrivate(game.handSize())
+	rounds := game.draws() + 1
+	for i := 0; i < rounds && game.live() > 1; i++ {
+		if i > 0 {
+			game.draw()
+		}
+		betSize, first := game.smallBet(), game.firstPostflop()
+		if i == 0 {
+			first = game.firstPreflop()
+		}
+		if 2*i >= rounds {
+			betSize = game.bigBet()
+		}
+		game.betting(betSize, first)
+	}
+	game.showdown()
+}
+
+// Deal the given number of cards to each player privately (i.e. face down)
+// Deals player to left of button first, ending with dealing to button. Seats
+// not in the hand are skipped.
+func (game *DrawGame) DealPrivate(cards int) {
+	game.dealPrivate(cards)
+}
+
+// Panics; draw games do not have shared cards.
+func (game *DrawGame) DealShared(cards int) {
+	panic("cannot deal shared card in draw game")
+}
+
+// Panics; draw games do not have public cards. (i.e. cards dealt to an individual player face up)
+func (game *DrawGame) DealPublic(cards int) {
+	panic("cannot deal public card in draw game")
+}
+
+// ----- DRAW GAME FUNCTIONS -------------------------------------------------
+
+// Prepare this game for a new round of play. Returns false if there are not
+// enough players to deal a hand.
+func (game *DrawGame) init() bool {
+	if !game.startHand() {
+		return false
+	}
+	game.postBlinds()
+	return true
+}
+
+// Report the number of cards in a hand of this game.
+func (game *DrawGame) handSize() int {
+	if game.game == Badugi {
+		return CardsPerBadugiHand
+	}
+	return 5
+}
+
+// Report the number of draws in a hand of this game.
+func (game *DrawGame) draws() int {
+	switch game.game {
+	case Deuce73Draw, Badugi:
+		return 3
+	}
+	return 1
+}
+
+// Report how hands are ranked in this game.
+func (game *DrawGame) ranking() HandRanking {
+	switch game.game {
+	case Deuce7, Deuce73Draw:
+		return Low27
+	case Badugi:
+		return LowBadugi
+	}
+	return High
+}
+
+// Let each player in the hand throw away cards and draw replacements,
+// starting to the left of the button and ending with the button.
+func (game *DrawGame) draw() {
+	cnt := len(game.players)
+	for j := 1; j <= cnt; j++ {
+		p := (game.dealer + j) % cnt
+		if game.folded[p] {
+			continue
+		}
+		hand := append([]Card(nil), game.hands[p]...)
+		game.replace(p, game.players[p].Discard(hand))
+	}
+}
+
+// Take the given cards out of the seat's hand and deal it as many new ones.
+// Cards the player does not hold are ignored. When the stub runs out, the
+// discards so far are shuffled to make a new one; if there are none, the
+// player's own discards have to be used.
+func (game *DrawGame) replace(seat int, cards []Card) {
+	var discards []Card
+	kept := game.hands[seat][:0]
+	for _, card := range game.hands[seat] {
+		if containsCard(cards, card) {
+			discards = append(discards, card)
+		} else {
+			kept = append(kept, card)
+		}
+	}
+	game.hands[seat] = kept
+	n := len(discards)
+	for i := 0; i < n; i++ {
+		if game.deck.Empty() {
+			game.deck.ReshuffleDiscards()
+		}
+		if game.deck.Empty() {
+			game.deck.Discard(discards...)
+			discards = nil
+			game.deck.ReshuffleDiscards()
+		}
+		card := game.deck.MustDeal()
+		game.hands[seat] = append(game.hands[seat], card)
+		game.players[seat].DealPrivate(card)
+	}
+	game.deck.Discard(discards...)
+}
+
+// Award the main pot and any side pots to the best hand(s) eligible for
+// each. Folded players cannot win.
+func (game *DrawGame) showdown() {
+	contenders := make([]Contender, len(game.players))
+	for p := range game.players {
+		if game.folded[p] {
+			contenders[p] = Contender{Hand: NoHand, Low: NoLow}
+			continue
+		}
+		contenders[p] = game.evaluate(game.hands[p])
+	}
+	game.award(contenders, false)
+}
+
+// Evaluate the given hand with this game's hand ranking. Every ranking puts
+// the best hands lowest, so the value is used as the main hand. A hand that
+// cannot be evaluated can win nothing.
+func (game *DrawGame) evaluate(hand []Card) Contender {
+	var val uint64
+	var err error
+	switch game.ranking() {
+	case High:
+		var high HandValue
+		high, err = EvaluateHand(hand)
+		val = uint64(high)
+	case LowA5:
+		var low LowValue
+		low, err = EvaluateForLowA5(hand, false)
+		val = uint64(low)
+	case Low27:
+		var low Low27Value
+		low, err = EvaluateForLow27(hand)
+		val = uint64(low)
+	case LowBadugi:
+		var badugi BadugiValue
+		badugi, err = EvaluateForBadugi(hand)
+		val = uint64(badugi)
+	}
+	if err != nil {
+		return Contender{Hand: NoHand, Low: NoLow}
+	}
+	return Contender{Hand: val, Low: NoLow}
+}
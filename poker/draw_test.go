@@ -0,0 +1,64 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+var _ Game = &DrawGame{}
+
+func Test_can_play_five_card_draw(t *testing.T) {
+	// aces draw three and miss; kings draw three and make a full house
+	players := []*testPlayer{{chips: 1000, draws: []string{"7c 4h 2s"}}, {chips: 1000, draws: []string{"Qh 9s 3c"}}}
+	deck := stackedDeck(t, "As Kc Ad Kd 7c Qh 4h 9s 2s 3c 5d 8c Jh Kh 6d 6s")
+	game := newDrawGame(t, players, deck, FiveDraw, NoLimit)
+	game.Play()
+	expectChips(t, players, 900, 1100)
+	if PrintHand(players[0].hole) != "(As,Ad,5d,8c,Jh)" {
+		t.Fatalf("expected (As,Ad,5d,8c,Jh) but was %s", PrintHand(players[0].hole))
+	}
+}
+
+func Test_draw_reshuffles_discards_when_stub_runs_out(t *testing.T) {
+	// two cards are left after the deal, so seat 1 draws from seat 0's
+	// discards and makes the best deuce-to-seven low
+	players := []*testPlayer{{chips: 1000, draws: []string{"7h 7c"}}, {chips: 1000, draws: []string{"Kd"}}}
+	deck := stackedDeck(t, "2c 2d 3d 3h 4h 4s 7h 5c 7c Kd 5s 8d")
+	game := newDrawGame(t, players, deck, Deuce7, FixedLimit)
+	game.Play()
+	expectChips(t, players, 900, 1100)
+	if PrintHand(players[1].hole) != "(2d,3h,4s,5c,7h)" {
+		t.Fatalf("expected (2d,3h,4s,5c,7h) but was %s", PrintHand(players[1].hole))
+	}
+}
+
+func Test_can_play_badugi(t *testing.T) {
+	// both stand pat through all three draws
+	players := []*testPlayer{{chips: 1000}, {chips: 1000}}
+	deck := stackedDeck(t, "Ac Kc 2d Qd 3h Jh 4s Ts")
+	game := newDrawGame(t, players, deck, Badugi, FixedLimit)
+	game.Play()
+	expectChips(t, players, 1100, 900)
+	if len(players[0].hole) != CardsPerBadugiHand {
+		t.Fatalf("expected 4 cards but was %s", PrintHand(players[0].hole))
+	}
+}
+
+func Test_draw_game_seats_at_most_max_seats(t *testing.T) {
+	players := make([]*testPlayer, MaxSeats+1)
+	for i := range players {
+		players[i] = &testPlayer{chips: 1000}
+	}
+	if _, err := NewDrawGame(gamePlayers(players), NewPokerDeck(), FiveDraw, FixedLimit, NewBlinds(50, 100), 0); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+}
+
+func newDrawGame(t *testing.T, players []*testPlayer, deck Deck, variant GameType, limit GameLimit) *DrawGame {
+	game, err := NewDrawGame(gamePlayers(players), deck, variant, limit, NewBlinds(50, 100), 0)
+	if err != nil {
+		t.Fatalf("cannot create draw game: %v", err)
+	}
+	return game
+}
@@ -0,0 +1,413 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+const (
+	BoardSize = 5
+	// Default limit on the number of boards enumerated before falling back
+	// to Monte Carlo simulation.
+	DefaultMaxExhaustive = 250000
+	// Default number of boards simulated by Monte Carlo.
+	DefaultIterations = 100000
+)
+
+var (
+	TooFewPlayers    = fmt.Errorf("equity needs at least two players")
+	DuplicateCard    = fmt.Errorf("card appears more than once")
+	BoardTooLarge    = fmt.Errorf("board cannot hold more than 5 cards")
+	UnsupportedGame  = fmt.Errorf("equity is only supported for Hold'em and Omaha games")
+	InvalidHoleCards = fmt.Errorf("wrong number of hole cards for game")
+)
+
+// ----- PUBLIC EQUITY API ---------------------------------------------------
+
+// Settings for an equity calculation. The zero value calculates Hold'em
+// equity from preflop with the default limits.
+type EquityOptions struct {
+	// Game whose hand rules apply. (i.e. Holdem, Omaha, Omaha5, etc)
+	Game GameType
+	// Community cards already dealt, if any.
+	Board []Card
+	// Known cards that cannot come on the board, such as folded hands.
+	Dead []Card
+	// Enumerate every possible board if there are no more than this many,
+	// otherwise simulate. Negative values always simulate.
+	MaxExhaustive int
+	// Number of boards to simulate.
+	Iterations int
+	// Seed for the simulation. Results are reproducible for a given seed
+	// and number of workers.
+	Seed int64
+	// Number of goroutines to spread the work across. Defaults to the number
+	// of CPUs.
+	Workers int
+}
+
+// A player's share of the outcomes of an equity calculation. Win, Tie and
+// Lose are the fractions of boards on which the player won outright, split
+// the pot and lost. Share is the fraction of the pot the player can expect
+// to take, counting a tie between n players as 1/n of a win. In hi/lo games
+// half the pot goes to the best qualifying low, so only scooping both halves
+// counts as a win, and taking part of the pot counts as a tie.
+type Equity struct {
+	Win   float64
+	Tie   float64
+	Lose  float64
+	Share float64
+}
+
+// Result of an equity calculation: each player's equity in the order their
+// hands were given, how many boards were evaluated and whether those were
+// every possible board or a random sample.
+type EquityResult struct {
+	Players    []Equity
+	Boards     int
+	Exhaustive bool
+}
+
+// Calculate each player's equity given their hole cards and the options.
+func CalculateEquity(hands [][]Card, opts EquityOptions) (EquityResult, error) {
+	if len(hands) < 2 {
+		return EquityResult{}, TooFewPlayers
+	}
+	holeCards, err := holeCardsFor(opts.Game)
+	if err != nil {
+		return EquityResult{}, err
+	}
+	for _, hand := range hands {
+		if len(hand) != holeCards {
+			return EquityResult{}, InvalidHoleCards
+		}
+	}
+	if len(opts.Board) > BoardSize {
+		return EquityResult{}, BoardTooLarge
+	}
+	known := append([][]Card{opts.Board, opts.Dead}, hands...)
+	stub, err := remainingCards(known...)
+	if err != nil {
+		return EquityResult{}, err
+	}
+	sim := &equitySim{
+		hands:  hands,
+		board:  opts.Board,
+		stub:   stub,
+		omaha:  usesOmahaRules(opts.Game),
+		hiLo:   isHiLoGame(opts.Game),
+		needed: BoardSize - len(opts.Board),
+	}
+	if len(stub) < sim.needed {
+		return EquityResult{}, EmptyDeck
+	}
+	return sim.run(opts), nil
+}
+
+// ----- EQUITY FUNCTIONS ----------------------------------------------------
+
+// Does the given game make hands from exactly two hole cards and three board
+// cards?
+func usesOmahaRules(game GameType) bool {
+	switch game {
+	case Omaha, OmahaHL, Omaha5, Omaha5HL, Courchevel, CourchevelHL:
+		return true
+	}
+	return false
+}
+
+// Is the given game split between the best high and the best low?
+func isHiLoGame(game GameType) bool {
+	switch game {
+	case OmahaHL, Omaha5HL, CourchevelHL:
+		return true
+	}
+	return false
+}
+
+// Report how many hole cards each player holds at showdown in the given
+// community card game.
+func holeCardsFor(game GameType) (int, error) {
+	switch game {
+	case Holdem:
+		return 2, nil
+	case Omaha, OmahaHL:
+		return 4, nil
+	case Omaha5, Omaha5HL, Courchevel, CourchevelHL:
+		return 5, nil
+	}
+	return 0, UnsupportedGame
+}
+
+// Return the cards of a full deck that are not among the known cards, or
+// DuplicateCard if any card is known more than once.
+func remainingCards(known ...[]Card) ([]Card, error) {
+	seen := make(map[Card]bool)
+	for _, cards := range known {
+		for _, card := range cards {
+			if seen[card] {
+				return nil, DuplicateCard
+			}
+			seen[card] = true
+		}
+	}
+	deck := NewPokerDeck()
+	stub := make([]Card, 0, len(deck.cards))
+	for _, card := range deck.cards {
+		if !seen[card] {
+			stub = append(stub, card)
+		}
+	}
+	return stub, nil
+}
+
+// State shared by the workers of an equity calculation.
+type equitySim struct {
+	hands  [][]Card
+	board  []Card
+	stub   []Card
+	omaha  bool
+	hiLo   bool
+	needed int
+}
+
+// Tally of outcomes gathered by a single worker.
+type equityTally struct {
+	boards int
+	wins   []int
+	ties   []int
+	shares []float64
+}
+
+// Run the calculation, exhaustively if there are few enough boards left to
+// deal, and combine the workers' tallies into the result.
+func (sim *equitySim) run(opts EquityOptions) EquityResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	maxExhaustive := opts.MaxExhaustive
+	if maxExhaustive == 0 {
+		maxExhaustive = DefaultMaxExhaustive
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+	exhaustive := maxExhaustive > 0 && choose(len(sim.stub), sim.needed) <= maxExhaustive
+
+	tallies := make([]*equityTally, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		tallies[w] = sim.newTally()
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			if exhaustive {
+				sim.enumerate(tallies[w], w, workers)
+			} else {
+				n := iterations / workers
+				if w < iterations%workers {
+					n++
+				}
+				sim.simulate(tallies[w], rand.New(rand.NewSource(opts.Seed+int64(w))), n)
+			}
+		}(w)
+	}
+	wg.Wait()
+	return summarize(tallies, len(sim.hands), exhaustive)
+}
+
+// Create an empty tally for this calculation.
+func (sim *equitySim) newTally() *equityTally {
+	return newTally(len(sim.hands))
+}
+
+// Create an empty tally for the given number of players.
+func newTally(players int) *equityTally {
+	return &equityTally{
+		wins:   make([]int, players),
+		ties:   make([]int, players),
+		shares: make([]float64, players),
+	}
+}
+
+// Combine the workers' tallies into each player's equity.
+func summarize(tallies []*equityTally, players int, exhaustive bool) EquityResult {
+	total := newTally(players)
+	for _, t := range tallies {
+		total.boards += t.boards
+		for i := 0; i < players; i++ {
+			total.wins[i] += t.wins[i]
+			total.ties[i] += t.ties[i]
+			total.shares[i] += t.shares[i]
+		}
+	}
+	result := EquityResult{Players: make([]Equity, players), Boards: total.boards, Exhaustive: exhaustive}
+	if total.boards == 0 {
+		return result
+	}
+	n := float64(total.boards)
+	for i := 0; i < players; i++ {
+		result.Players[i] = Equity{
+			Win:   float64(total.wins[i]) / n,
+			Tie:   float64(total.ties[i]) / n,
+			Lose:  float64(total.boards-total.wins[i]-total.ties[i]) / n,
+			Share: total.shares[i] / n,
+		}
+	}
+	return result
+}
+
+// Evaluate every possible completion of the board whose first card is at
+// stub index offset, offset+workers, offset+2*workers and so on, so that the
+// workers share the boards out between them without walking each other's.
+// Striding through the first index, rather than splitting it into runs,
+// keeps the workers' shares even, as low indices begin the most boards.
+func (sim *equitySim) enumerate(tally *equityTally, offset, workers int) {
+	board := make([]Card, BoardSize)
+	copy(board, sim.board)
+	scratch := sim.newScratch()
+	if sim.needed == 0 {
+		if offset == 0 {
+			sim.score(tally, board, scratch)
+		}
+		return
+	}
+	idx := make([]int, sim.needed)
+	for first := offset; first <= len(sim.stub)-len(idx); first += workers {
+		for i := range idx {
+			idx[i] = first + i
+		}
+		for {
+			for i, j := range idx {
+				board[len(sim.board)+i] = sim.stub[j]
+			}
+			sim.score(tally, board, scratch)
+			// advance to the next combination of stub indices, keeping the
+			// first one fixed
+			i := len(idx) - 1
+			for i > 0 && idx[i] == len(sim.stub)-len(idx)+i {
+				i--
+			}
+			if i == 0 {
+				break
+			}
+			idx[i]++
+			for j := i + 1; j < len(idx); j++ {
+				idx[j] = idx[j-1] + 1
+			}
+		}
+	}
+}
+
+// Evaluate n randomly completed boards.
+func (sim *equitySim) simulate(tally *equityTally, rng *rand.Rand, n int) {
+	board := make([]Card, BoardSize)
+	copy(board, sim.board)
+	stub := make([]Card, len(sim.stub))
+	copy(stub, sim.stub)
+	scratch := sim.newScratch()
+	for ; n > 0; n-- {
+		// partial Fisher-Yates shuffle of just the cards we need
+		for i := 0; i < sim.needed; i++ {
+			j := i + rng.Intn(len(stub)-i)
+			stub[i], stub[j] = stub[j], stub[i]
+			board[len(sim.board)+i] = stub[i]
+		}
+		sim.score(tally, board, scratch)
+	}
+}
+
+// Per-worker buffers reused from board to board.
+type equityScratch struct {
+	cards  []Card
+	values []uint16
+	lows   []LowValue
+}
+
+// Create the buffers one worker needs to score boards.
+func (sim *equitySim) newScratch() *equityScratch {
+	return &equityScratch{
+		cards:  make([]Card, 2+BoardSize),
+		values: make([]uint16, len(sim.hands)),
+		lows:   make([]LowValue, len(sim.hands)),
+	}
+}
+
+// Evaluate every player's hand on a complete board and add the outcome to
+// the tally. In hi/lo games the high and low halves are shared out
+// separately.
+func (sim *equitySim) score(tally *equityTally, board []Card, scratch *equityScratch) {
+	var best uint16 = 0xFFFF
+	bestLow := NoLow
+	for i, hole := range sim.hands {
+		var val uint16
+		low := NoLow
+		if sim.omaha {
+			val, low = evalOmaha(hole, board, sim.hiLo)
+		} else {
+			copy(scratch.cards, hole)
+			copy(scratch.cards[2:], board)
+			val = evalHand(scratch.cards)
+		}
+		scratch.values[i] = val
+		scratch.lows[i] = low
+		if val < best {
+			best = val
+		}
+		if low < bestLow {
+			bestLow = low
+		}
+	}
+	highs, lows := 0, 0
+	for i, val := range scratch.values {
+		if val == best {
+			highs++
+		}
+		if bestLow != NoLow && scratch.lows[i] == bestLow {
+			lows++
+		}
+	}
+	// the high takes the whole pot unless somebody makes a low
+	high := 1.0
+	if lows > 0 {
+		high = 0.5
+	}
+	for i, val := range scratch.values {
+		var share float64
+		if val == best {
+			share += high / float64(highs)
+		}
+		if lows > 0 && scratch.lows[i] == bestLow {
+			share += (1 - high) / float64(lows)
+		}
+		switch {
+		case share == 1:
+			tally.wins[i]++
+		case share > 0:
+			tally.ties[i]++
+		}
+		tally.shares[i] += share
+	}
+	tally.boards++
+}
+
+// Compute n-choose-k, saturating rather than overflowing for large results.
+func choose(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	rv := 1
+	for i := 1; i <= k; i++ {
+		rv = rv * (n - k + i) / i
+		if rv > 1<<40 {
+			return 1 << 40
+		}
+	}
+	return rv
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_can_calculate_exact_equity_on_flop(t *testing.T) {
+	hands := [][]Card{makeHand([]string{"Ah", "Kh"}), makeHand([]string{"Qs", "Qd"})}
+	board := makeHand([]string{"2h", "7h", "Qc"})
+	result := equity(t, hands, EquityOptions{Board: board, Workers: 3})
+	if !result.Exhaustive || result.Boards != 990 {
+		t.Fatalf("expected 990 boards exhaustively but was %d (%v)", result.Boards, result.Exhaustive)
+	}
+	// the nut flush draw needs running cards to beat a set
+	ak, qq := result.Players[0], result.Players[1]
+	if ak.Share < 0.25 || ak.Share > 0.35 {
+		t.Fatalf("expected about 30%% equity but was %.3f", ak.Share)
+	}
+	expectClose(t, ak.Share+qq.Share, 1)
+	expectClose(t, ak.Win+ak.Tie+ak.Lose, 1)
+	expectClose(t, ak.Win, qq.Lose)
+}
+
+func Test_exact_equity_does_not_depend_on_workers(t *testing.T) {
+	hands := [][]Card{makeHand([]string{"Ah", "Kh"}), makeHand([]string{"Qs", "Qd"})}
+	board := makeHand([]string{"2h", "7h", "Qc"})
+	expected := equity(t, hands, EquityOptions{Board: board, Workers: 1})
+	for _, workers := range []int{2, 7, 64} {
+		result := equity(t, hands, EquityOptions{Board: board, Workers: workers})
+		if result.Boards != expected.Boards || result.Players[0] != expected.Players[0] {
+			t.Fatalf("expected %v over %d boards but was %v over %d with %d workers",
+				expected.Players[0], expected.Boards, result.Players[0], result.Boards, workers)
+		}
+	}
+}
+
+func Test_can_count_ties(t *testing.T) {
+	// both players play the board
+	hands := [][]Card{makeHand([]string{"2c", "3d"}), makeHand([]string{"2d", "3c"})}
+	board := makeHand([]string{"Ts", "Js", "Qh", "Kd", "Ac"})
+	result := equity(t, hands, EquityOptions{Board: board})
+	if result.Boards != 1 {
+		t.Fatalf("expected 1 board but was %d", result.Boards)
+	}
+	for _, eq := range result.Players {
+		expectClose(t, eq.Tie, 1)
+		expectClose(t, eq.Share, 0.5)
+	}
+}
+
+func Test_monte_carlo_equity_is_reproducible(t *testing.T) {
+	hands := [][]Card{makeHand([]string{"As", "Ad"}), makeHand([]string{"Ks", "Kd"})}
+	opts := EquityOptions{Iterations: 20000, Seed: 42, Workers: 2}
+	a := equity(t, hands, opts)
+	b := equity(t, hands, opts)
+	if a.Exhaustive || a.Boards != 20000 {
+		t.Fatalf("expected 20000 simulated boards but was %d (%v)", a.Boards, a.Exhaustive)
+	}
+	if a.Players[0] != b.Players[0] {
+		t.Fatalf("expected %v but was %v", a.Players[0], b.Players[0])
+	}
+	// aces are about an 82% favourite over kings
+	if share := a.Players[0].Share; share < 0.79 || share > 0.85 {
+		t.Fatalf("expected about 82%% equity but was %.3f", share)
+	}
+}
+
+func Test_can_calculate_omaha_equity(t *testing.T) {
+	hands := [][]Card{makeHand([]string{"As", "Ad", "Ks", "Kd"}), makeHand([]string{"9h", "8h", "7c", "6c"})}
+	board := makeHand([]string{"Ah", "5h", "4c"})
+	result := equity(t, hands, EquityOptions{Game: Omaha, Board: board, Dead: makeHand([]string{"2s"})})
+	if !result.Exhaustive || result.Boards != 780 {
+		t.Fatalf("expected 780 boards exhaustively but was %d (%v)", result.Boards, result.Exhaustive)
+	}
+	expectClose(t, result.Players[0].Share+result.Players[1].Share, 1)
+}
+
+func Test_can_calculate_omaha_hi_lo_equity(t *testing.T) {
+	hands := [][]Card{makeHand([]string{"Ac", "2d", "Js", "Jh"}), makeHand([]string{"Kc", "Ks", "9h", "9d"})}
+	// kings win the high and ace-deuce the low
+	board := makeHand([]string{"3c", "4d", "7h", "Kd", "Qs"})
+	result := equity(t, hands, EquityOptions{Game: OmahaHL, Board: board})
+	for _, eq := range result.Players {
+		expectClose(t, eq.Tie, 1)
+		expectClose(t, eq.Share, 0.5)
+	}
+	// without a low the kings scoop
+	board = makeHand([]string{"Kd", "9c", "6h", "Th", "5s"})
+	result = equity(t, hands, EquityOptions{Game: OmahaHL, Board: board})
+	expectClose(t, result.Players[1].Win, 1)
+	expectClose(t, result.Players[1].Share, 1)
+}
+
+func Test_rejects_invalid_equity_requests(t *testing.T) {
+	aa := makeHand([]string{"As", "Ad"})
+	if _, err := CalculateEquity([][]Card{aa}, EquityOptions{}); err != TooFewPlayers {
+		t.Fatalf("expected TooFewPlayers but was %v", err)
+	}
+	if _, err := CalculateEquity([][]Card{aa, aa}, EquityOptions{}); err != DuplicateCard {
+		t.Fatalf("expected DuplicateCard but was %v", err)
+	}
+	kk := makeHand([]string{"Ks", "Kd"})
+	if _, err := CalculateEquity([][]Card{aa, kk}, EquityOptions{Game: Omaha}); err != InvalidHoleCards {
+		t.Fatalf("expected InvalidHoleCards but was %v", err)
+	}
+	if _, err := CalculateEquity([][]Card{aa, kk}, EquityOptions{Game: Razz}); err != UnsupportedGame {
+		t.Fatalf("expected UnsupportedGame but was %v", err)
+	}
+}
+
+func equity(t *testing.T, hands [][]Card, opts EquityOptions) EquityResult {
+	result, err := CalculateEquity(hands, opts)
+	if err != nil {
+		t.Fatalf("cannot calculate equity: %v", err)
+	}
+	return result
+}
+
+func expectClose(t *testing.T, actual, expected float64) {
+	if math.Abs(actual-expected) > 1e-9 {
+		t.Fatalf("expected %f but was %f", expected, actual)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// Size in bytes of a server seed.
+const ServerSeedSize = 32
+
+var (
+	CommitmentMismatch = fmt.Errorf("server seed does not match commitment")
+	ShuffleStarted     = fmt.Errorf("client seeds cannot change once the deck is shuffled")
+)
+
+// ----- PUBLIC PROVABLY FAIR SHUFFLE API ------------------------------------
+
+// Commit-reveal protocol for a shuffle the players can check. Before the
+// hand the server picks a secret seed and publishes its Commitment. Players
+// then contribute seeds of their own, the deck is shuffled from all of the
+// seeds together and, once the hand is over, the server reveals its seed so
+// that anyone can rebuild the deck with VerifyDeck. The server cannot steer
+// the shuffle because it committed to its seed before seeing the players',
+// and the players cannot steer it because they never see the server's.
+//
+// The deck order is derived as follows, so it can be checked independently
+// of this package. The client seeds are concatenated, each preceded by its
+// length as a 64-bit big-endian integer, to give a message M. Block n of the
+// random stream is HMAC-SHA256 keyed with the server seed over M followed by
+// n as a 64-bit big-endian integer, for n = 0, 1, 2 and so on. The stream is
+// read 4 bytes at a time as big-endian integers; to draw a number below max,
+// values at or above 2^32 - (2^32 mod max) are discarded and the first value
+// below that is taken modulo max. Starting from an unshuffled deck (the
+// order NewPokerDeck creates, which Shuffle restores each time, so a deck
+// can be reused from hand to hand), for i from 51 down to 1 a number j below
+// i+1 is drawn and cards i and j are swapped.
+type FairShuffle struct {
+	serverSeed  []byte
+	clientSeeds []string
+	started     bool
+	// Hex-encoded SHA-256 hash of the server seed, to be published before
+	// the hand is dealt.
+	Commitment string
+}
+
+// Start a provably fair shuffle with a new secret server seed.
+func NewFairShuffle() (*FairShuffle, error) {
+	seed := make([]byte, ServerSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return &FairShuffle{serverSeed: seed, Commitment: Commitment(seed)}, nil
+}
+
+// Add a seed contributed by a player. Returns ShuffleStarted if the deck has
+// already been shuffled from this shuffle's source.
+func (fs *FairShuffle) AddClientSeed(seed string) error {
+	if fs.started {
+		return ShuffleStarted
+	}
+	fs.clientSeeds = append(fs.clientSeeds, seed)
+	return nil
+}
+
+// Return the client seeds added so far, in the order they were added.
+func (fs *FairShuffle) ClientSeeds() []string {
+	return append([]string(nil), fs.clientSeeds...)
+}
+
+// Return the source to shuffle the deck with. (i.e. by way of SetSource)
+// After this the client seeds can no longer change. The source reproduces
+// the committed deck for one shuffle only.
+func (fs *FairShuffle) Source() RandomSource {
+	fs.started = true
+	return NewFairSource(fs.serverSeed, fs.clientSeeds...)
+}
+
+// Reveal the server seed. This must only be done once the hand is over.
+func (fs *FairShuffle) Reveal() []byte {
+	return append([]byte(nil), fs.serverSeed...)
+}
+
+// Return the hex-encoded SHA-256 hash of the given server seed.
+func Commitment(serverSeed []byte) string {
+	sum := sha256.Sum256(serverSeed)
+	return hex.EncodeToString(sum[:])
+}
+
+// Create the source a provably fair shuffle draws from for the given seeds.
+func NewFairSource(serverSeed []byte, clientSeeds ...string) RandomSource {
+	var msg []byte
+	var size [8]byte
+	for _, seed := range clientSeeds {
+		binary.BigEndian.PutUint64(size[:], uint64(len(seed)))
+		msg = append(append(msg, size[:]...), seed...)
+	}
+	return &fairSource{mac: hmac.New(sha256.New, serverSeed), msg: msg}
+}
+
+// Rebuild the deck order of a provably fair shuffle from the revealed server
+// seed and the client seeds, after checking the server seed against the
+// commitment published before the hand. Returns CommitmentMismatch if the
+// server seed is not the one committed to.
+func VerifyDeck(serverSeed []byte, commitment string, clientSeeds []string) ([]Card, error) {
+	if !hmac.Equal([]byte(Commitment(serverSeed)), []byte(commitment)) {
+		return nil, CommitmentMismatch
+	}
+	deck := NewPokerDeckWithSource(NewFairSource(serverSeed, clientSeeds...))
+	deck.Shuffle()
+	return deck.cards, nil
+}
+
+// ----- PROVABLY FAIR SHUFFLE FUNCTIONS -------------------------------------
+
+// HMAC-SHA256 stream in counter mode.
+type fairSource struct {
+	mac     hash.Hash
+	msg     []byte
+	counter uint64
+	block   []byte
+}
+
+func (src *fairSource) Intn(max int) int {
+	if max <= 0 {
+		return -1
+	}
+	// reject values that would make some results more likely than others
+	limit := (1 << 32) - (1<<32)%uint64(max)
+	for {
+		if v := uint64(src.next()); v < limit {
+			return int(v % uint64(max))
+		}
+	}
+}
+
+// Read the next 4 bytes of the stream as a big-endian integer.
+func (src *fairSource) next() uint32 {
+	if len(src.block) < 4 {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], src.counter)
+		src.counter++
+		src.mac.Reset()
+		src.mac.Write(src.msg)
+		src.mac.Write(n[:])
+		src.block = src.mac.Sum(src.block[:0])
+	}
+	v := binary.BigEndian.Uint32(src.block)
+	src.block = src.block[4:]
+	return v
+}
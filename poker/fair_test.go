@@ -0,0 +1,100 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_can_verify_fair_shuffle(t *testing.T) {
+	fs, err := NewFairShuffle()
+	if err != nil {
+		t.Fatalf("cannot start shuffle: %v", err)
+	}
+	commitment := fs.Commitment
+	fs.AddClientSeed("alice")
+	fs.AddClientSeed("bob")
+	deck := NewPokerDeck()
+	deck.SetSource(fs.Source())
+	deck.Shuffle()
+	if err := fs.AddClientSeed("mallory"); err != ShuffleStarted {
+		t.Fatalf("expected ShuffleStarted but was %v", err)
+	}
+	cards, err := VerifyDeck(fs.Reveal(), commitment, fs.ClientSeeds())
+	if err != nil {
+		t.Fatalf("cannot verify deck: %v", err)
+	}
+	if PrintHand(cards) != PrintHand(deck.cards) {
+		t.Fatalf("expected %s but was %s", PrintHand(deck.cards), PrintHand(cards))
+	}
+}
+
+func Test_can_verify_fair_shuffle_of_reused_deck(t *testing.T) {
+	fs, err := NewFairShuffle()
+	if err != nil {
+		t.Fatalf("cannot start shuffle: %v", err)
+	}
+	fs.AddClientSeed("alice")
+	// the deck has already been shuffled and dealt from for an earlier hand
+	deck := NewPokerDeck()
+	deck.Shuffle()
+	deck.MustDeal()
+	deck.SetSource(fs.Source())
+	deck.Shuffle()
+	cards, err := VerifyDeck(fs.Reveal(), fs.Commitment, fs.ClientSeeds())
+	if err != nil {
+		t.Fatalf("cannot verify deck: %v", err)
+	}
+	if PrintHand(cards) != PrintHand(deck.cards) {
+		t.Fatalf("expected %s but was %s", PrintHand(deck.cards), PrintHand(cards))
+	}
+}
+
+func Test_fair_shuffle_rejects_wrong_seed(t *testing.T) {
+	fs, err := NewFairShuffle()
+	if err != nil {
+		t.Fatalf("cannot start shuffle: %v", err)
+	}
+	seed := fs.Reveal()
+	seed[0] ^= 1
+	if _, err := VerifyDeck(seed, fs.Commitment, nil); err != CommitmentMismatch {
+		t.Fatalf("expected CommitmentMismatch but was %v", err)
+	}
+}
+
+func Test_client_seeds_change_fair_shuffle(t *testing.T) {
+	seed := []byte("server seed")
+	commitment := Commitment(seed)
+	a, _ := VerifyDeck(seed, commitment, []string{"alice", "bob"})
+	b, _ := VerifyDeck(seed, commitment, []string{"alice", "bob"})
+	if PrintHand(a) != PrintHand(b) {
+		t.Fatalf("expected %s but was %s", PrintHand(a), PrintHand(b))
+	}
+	// seeds are length-prefixed, so moving the boundary between them counts
+	for _, seeds := range [][]string{{"alice", "bobb"}, {"bob", "alice"}, {"aliceb", "ob"}} {
+		c, _ := VerifyDeck(seed, commitment, seeds)
+		if PrintHand(a) == PrintHand(c) {
+			t.Fatalf("expected seeds %v to shuffle differently", seeds)
+		}
+	}
+}
+
+func Test_fair_source_stays_in_range(t *testing.T) {
+	src := NewFairSource([]byte("server seed"), "client")
+	var seen [7]int
+	for i := 0; i < 7000; i++ {
+		n := src.Intn(7)
+		if n < 0 || n >= 7 {
+			t.Fatalf("expected value in [0,7) but was %d", n)
+		}
+		seen[n]++
+	}
+	for n, cnt := range seen {
+		if cnt < 850 || cnt > 1150 {
+			t.Fatalf("expected about 1000 draws of %d but was %d", n, cnt)
+		}
+	}
+	if src.Intn(0) != -1 {
+		t.Fatalf("expected -1 for empty range")
+	}
+}
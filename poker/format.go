@@ -0,0 +1,157 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Styles for rendering cards.
+const (
+	// Rank and suit letter, as returned by Card.String. (e.g. "Td")
+	ASCIIStyle = iota
+	// Rank and unicode suit symbol. (e.g. "T♦")
+	SymbolStyle
+	// The card's own character from the unicode playing cards block. (e.g.
+	// U+1F0CA for the ten of diamonds)
+	PlayingCardStyle
+	// Rank and suit letter coloured for a four-colour deck with ANSI escape
+	// codes: spades in the terminal's default colour, hearts red, diamonds
+	// blue and clubs green.
+	ColorStyle
+)
+
+const ansiReset = "\x1b[0m"
+
+// Jokers in the unicode playing cards block.
+const (
+	blackJoker = 0x1F0CF
+	whiteJoker = 0x1F0DF
+)
+
+// ----- PUBLIC CARD FORMATTING API ------------------------------------------
+
+// Return the representation of this card in the given style. (i.e.
+// ASCIIStyle, SymbolStyle, etc)
+func (card Card) Render(style int) string {
+	if card == Joker {
+		if style == PlayingCardStyle {
+			return string(rune(blackJoker))
+		}
+		return card.String()
+	}
+	switch style {
+	case SymbolStyle:
+		return rankStr[card.Rank()] + suitSymbol(card.Suit())
+	case PlayingCardStyle:
+		return string(playingCard(card))
+	case ColorStyle:
+		return suitColor(card.Suit()) + card.String() + ansiReset
+	}
+	return card.String()
+}
+
+// Format this card for the fmt package. The %s and %v verbs give the same
+// result as String, %u renders in SymbolStyle, %U in PlayingCardStyle and %c
+// in ColorStyle. Width and the '-' flag pad the result as they do for
+// strings. Any other verb formats the card's underlying integer.
+func (card Card) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 's', 'v':
+		s = card.String()
+	case 'u':
+		s = card.Render(SymbolStyle)
+	case 'U':
+		s = card.Render(PlayingCardStyle)
+	case 'c':
+		s = card.Render(ColorStyle)
+	default:
+		fmt.Fprintf(f, directive(f, verb), uint32(card))
+		return
+	}
+	fmt.Fprintf(f, directive(f, 's'), s)
+}
+
+// Return string representation of hand of cards in the given style. (i.e.
+// ASCIIStyle, SymbolStyle, etc)
+func FormatHand(hand []Card, style int) string {
+	rv := make([]string, len(hand))
+	for i, card := range hand {
+		rv[i] = card.Render(style)
+	}
+	return "(" + strings.Join(rv, ",") + ")"
+}
+
+// ----- CARD FORMATTING FUNCTIONS -------------------------------------------
+
+// Return the unicode symbol for the given suit.
+func suitSymbol(suit int) string {
+	switch suit {
+	case Club:
+		return "♣"
+	case Diamond:
+		return "♦"
+	case Heart:
+		return "♥"
+	}
+	return "♠"
+}
+
+// Return the ANSI escape code that colours the given suit.
+func suitColor(suit int) string {
+	switch suit {
+	case Club:
+		return "\x1b[32m"
+	case Diamond:
+		return "\x1b[34m"
+	case Heart:
+		return "\x1b[31m"
+	}
+	return "\x1b[39m"
+}
+
+// Return the character for this card in the unicode playing cards block.
+// Each suit has its own row of 16 code points, running ace, 2-10, jack,
+// knight, queen and king. We have no use for the knight.
+func playingCard(card Card) rune {
+	var base rune
+	switch card.Suit() {
+	case Club:
+		base = 0x1F0D0
+	case Diamond:
+		base = 0x1F0C0
+	case Heart:
+		base = 0x1F0B0
+	default:
+		base = 0x1F0A0
+	}
+	switch rank := card.Rank(); rank {
+	case Ace:
+		return base + 1
+	case Queen, King:
+		return base + rune(rank) + 3
+	default:
+		return base + rune(rank) + 2
+	}
+}
+
+// Rebuild the formatting directive for the given verb, keeping any flags,
+// width and precision it was given.
+func directive(f fmt.State, verb rune) string {
+	d := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			d += string(flag)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		d += strconv.Itoa(w)
+	}
+	if p, ok := f.Precision(); ok {
+		d += "." + strconv.Itoa(p)
+	}
+	return d + string(verb)
+}
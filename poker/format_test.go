@@ -0,0 +1,58 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_can_format_cards_in_each_style(t *testing.T) {
+	td := NewCard(Ten, Diamond)
+	for format, expected := range map[string]string{
+		"%s":   "Td",
+		"%v":   "Td",
+		"%u":   "T♦",
+		"%U":   "\U0001F0CA",
+		"%c":   "\x1b[34mTd\x1b[0m",
+		"%-4s": "Td  ",
+		"%d":   fmt.Sprintf("%d", uint32(td)),
+	} {
+		if s := fmt.Sprintf(format, td); s != expected {
+			t.Fatalf("expected %q but was %q for %s", expected, s, format)
+		}
+	}
+}
+
+func Test_spades_use_the_default_colour(t *testing.T) {
+	as := NewCard(Ace, Spade)
+	if s := fmt.Sprintf("%c", as); s != "\x1b[39mAs\x1b[0m" {
+		t.Fatalf("expected %q but was %q", "\x1b[39mAs\x1b[0m", s)
+	}
+}
+
+func Test_playing_card_code_points(t *testing.T) {
+	for s, expected := range map[string]rune{
+		"As": 0x1F0A1,
+		"2h": 0x1F0B2,
+		"Jd": 0x1F0CB,
+		"Qc": 0x1F0DD,
+		"Ks": 0x1F0AE,
+		"Jk": 0x1F0CF,
+	} {
+		card, _ := ParseCard(s)
+		if r := []rune(card.Render(PlayingCardStyle)); len(r) != 1 || r[0] != expected {
+			t.Fatalf("expected %U but was %U for %s", expected, r[0], s)
+		}
+	}
+}
+
+func Test_can_format_hands(t *testing.T) {
+	hand := makeHand([]string{"Ah", "Ts", "2c"})
+	if s := FormatHand(hand, SymbolStyle); s != "(A♥,T♠,2♣)" {
+		t.Fatalf("expected (A♥,T♠,2♣) but was %s", s)
+	}
+	if s := FormatHand(hand, ASCIIStyle); s != PrintHand(hand) {
+		t.Fatalf("expected %s but was %s", PrintHand(hand), s)
+	}
+}
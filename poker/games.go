@@ -39,14 +39,47 @@ const (
 
 // Record for specifying game blind bet amounts.
 type Blinds struct {
-	small uint32
-	big   uint32
+	small        uint32
+	big          uint32
+	ante         uint32
+	bigBlindAnte bool
+}
+
+// Create blinds of the given sizes, with no ante.
+func NewBlinds(small, big uint32) Blinds {
+	return Blinds{small: small, big: big}
+}
+
+// Return these blinds with every player also posting the given ante.
+func (blinds Blinds) WithAnte(ante uint32) Blinds {
+	blinds.ante = ante
+	blinds.bigBlindAnte = false
+	return blinds
+}
+
+// Return these blinds with the big blind also posting the given ante on
+// behalf of the whole table.
+func (blinds Blinds) WithBigBlindAnte(ante uint32) Blinds {
+	blinds.ante = ante
+	blinds.bigBlindAnte = true
+	return blinds
 }
 
 type Player interface {
 	DealPrivate(Card)
 	DealShared([]Card)
 	DealPublic(Card)
+	Win(uint32)
+	// Report the chips the player has on the table.
+	Chips() uint32
+	// Take chips from the player and put them in the pot.
+	Pay(uint32)
+	// Choose an action in the given round of betting.
+	Act(*BettingRound) Action
+	// Choose the cards to throw away from the given hand in a draw game or
+	// Irish. The player should drop them from its own hand; in draw games
+	// their replacements are dealt with DealPrivate.
+	Discard([]Card) []Card
 }
 
 // Interface for which all games must implement.
@@ -60,31 +93,26 @@ type Game interface {
 
 // Record for games with community cards (Hold'em, Omaha, etc)
 type CommunityGame struct {
-	players    []Player
-	deck       Deck
-	game       GameType
-	limit			 GameLimit
-	dealer     int
-	blinds     Blinds
-	maxRaises  int
+	table
+	game  GameType
+	board []Card
 }
 
-// Create a new community card game instance.
-func NewCommunityGame(players []Player, deck Deck, game GameType, limit GameLimit, blinds Blinds, maxRaises int) *CommunityGame {
-	return &CommunityGame{
-		players: players,
-		deck: deck,
-		game: game,
-		limit: limit,
-		dealer: 0,
-		blinds: blinds,
-		maxRaises: maxRaises,
+// Create a new community card game instance. Returns TableFull if there are
+// more than MaxSeats players.
+func NewCommunityGame(players []Player, deck Deck, game GameType, limit GameLimit, blinds Blinds, maxRaises int) (*CommunityGame, error) {
+	if len(players) > MaxSeats {
+		return nil, TableFull
 	}
+	return &CommunityGame{
+		table: newTable(players, deck, limit, blinds, maxRaises),
+		game:  game,
+	}, nil
 }
 
 // Hold'em/Omaha/OmahaHiLo/Omaha5/Omaha5HiLo/Courchevel/CourchevelHiLo/Irish:
 //	1. Post blinds
-//	2. Deal 2, 4 or 5 private cards, starting to left of big blind (2 for HE, 4 for Omaha/Irish, 5 for Omaha5/Courchevel)
+//	2. Deal 2, 4 or 5 private cards, starting to left of button (2 for HE, 4 for Omaha/Irish, 5 for Omaha5/Courchevel)
 //		a. Courchevel also deals 1 shared card at this point
 //	3. Round of betting
 //	4. Deal 3 shared cards (2 for Courchevel to complete flop)
@@ -96,43 +124,45 @@ func NewCommunityGame(players []Player, deck Deck, game GameType, limit GameLimi
 //	9. Round of betting
 //	10. Showdown
 func (game *CommunityGame) Play() {
-	game.init()
+	if !game.init() {
+		return
+	}
 	// pre-flop
 	game.dealHands()
 	if game.isCourchevel() {
 		game.DealShared(1)
 	}
-	game.betting()
+	game.betting(game.smallBet(), game.firstPreflop())
 	// flop
-	if game.isCourchevel() {
-		game.DealShared(2)
-	} else {
-		game.DealShared(3)
-	}
-	game.betting()
-	if game.isIrish() {
-		game.discard(2)
+	if game.live() > 1 {
+		if game.isCourchevel() {
+			game.DealShared(2)
+		} else {
+			game.DealShared(3)
+		}
+		game.betting(game.smallBet(), game.firstPostflop())
+		if game.isIrish() {
+			game.discard(2)
+		}
 	}
 	// turn
-	game.DealShared(1)
-	game.betting()
+	if game.live() > 1 {
+		game.DealShared(1)
+		game.betting(game.bigBet(), game.firstPostflop())
+	}
 	// river
-	game.DealShared(1)
-	game.betting()
+	if game.live() > 1 {
+		game.DealShared(1)
+		game.betting(game.bigBet(), game.firstPostflop())
+	}
 	game.showdown()
 }
 
 // Deal the given number of cards to each player privately (i.e. face down)
-// Deals player to left of big blind first, ending with dealing to big blind.
+// Deals player to left of button first, ending with dealing to button. Seats
+// not in the hand are skipped.
 func (game *CommunityGame) DealPrivate(cards int) {
-	cnt := len(game.players)
-	for i := 0; i < cards; i++ {
-		for j := 1; j <= cnt; j++ {
-			card := game.deck.Deal()
-			p := (game.dealer + j) % cnt
-			game.players[p].DealPrivate(card)
-		}
-	}
+	game.dealPrivate(cards)
 	game.pushHands()
 }
 
@@ -140,10 +170,13 @@ func (game *CommunityGame) DealPrivate(cards int) {
 func (game *CommunityGame) DealShared(cards int) {
 	dealt := make([]Card, cards)
 	for i := 0; i < cards; i++ {
-		dealt[i] = game.deck.Deal()
+		dealt[i] = game.deck.MustDeal()
 	}
+	game.board = append(game.board, dealt...)
 	for _, player := range game.players {
-		player.DealShared(dealt)
+		if player != nil {
+			player.DealShared(dealt)
+		}
 	}
 	game.pushHands()
 }
@@ -153,14 +186,17 @@ func (game *CommunityGame) DealPublic(cards int) {
 	panic("cannot deal public card in community game")
 }
 
-// Prepare this game for a new round of play.
-func (game *CommunityGame) init() {
-	// shuffle the cards
-	game.deck.Shuffle()
-	// advance dealer button
-	game.dealer = (game.dealer + 1) % len(game.players)
+// Prepare this game for a new round of play. Returns false if there are not
+// enough players to deal a hand.
+func (game *CommunityGame) init() bool {
+	// shuffle the cards, clear the table and advance dealer button
+	if !game.startHand() {
+		return false
+	}
+	game.board = game.board[:0]
 	// force the blinds to post
 	game.postBlinds()
+	return true
 }
 
 // Is this game a variant of Courchevel?
@@ -173,6 +209,17 @@ func (game *CommunityGame) isIrish() bool {
 	return game.game == Irish
 }
 
+// Is this game played with Omaha rules? (i.e. exactly two hole cards and
+// three board cards make a hand)
+func (game *CommunityGame) isOmaha() bool {
+	return usesOmahaRules(game.game)
+}
+
+// Is this game split between the best high and the best low?
+func (game *CommunityGame) isHiLo() bool {
+	return isHiLoGame(game.game)
+}
+
 // Deal each player their private hands for a community card game.
 func (game *CommunityGame) dealHands() {
 	switch game.game {
@@ -186,34 +233,76 @@ func (game *CommunityGame) dealHands() {
 }
 
 func (game *CommunityGame) pushHands() {}
-func (game *CommunityGame) betting() {}
-func (game *CommunityGame) showdown() {}
-func (game *CommunityGame) postBlinds() {}
-func (game *CommunityGame) discard(cards int) {}
-
-// 7Stud/7StudHiLo/Razz:
-//	1. Post antes, if applicable
-//	2. Deal 2 private cards and 1 public card
-//	3. Player with lowest door card posts bring-in
-//	4. Round of betting at lower limit, action starts to that player's left
-//	5. Deal 1 public card
-//	6. Round of betting at lower limit, action starts on player with strongest exposed board and continues to their left
-//	7. Deal 1 public card
-//	9. Round of betting at higher limit, action starts on player with strongest exposed board and continues to their left
-//	10. Deal 1 public card
-//	11. Round of betting at higher limit, action starts on player with strongest exposed board and continues to their left
-//	12. Deal 1 private card or shared card if insufficient cards left in deck to deal to every player remaining
-//	13. Round of betting at higher limit, action starts on player with strongest exposed board and continues to their left
-//	14. Showdown
-
-// 5Draw/2-7Lo/2-7TripleDrawLo/Badugi:
-//	1. Post ante and blinds
-//	2. Deal 4 (Badugi) or 5 (all others) private cards
-//	3. For each round (1 for 5Draw/2-7Lo or 3 for Triple Draw/Badugi):
-//		a. Round of betting
-//		b. Draw/discard
-//	4. Round of betting
-//	5. Showdown
+
+// Have each player in the hand throw away the given number of hole cards,
+// starting to the left of the button. Cards the player does not hold are
+// ignored, and if too few are thrown away the rest come from the end of the
+// hand; if too many, only the first ones count.
+func (game *CommunityGame) discard(cards int) {
+	cnt := len(game.players)
+	for j := 1; j <= cnt; j++ {
+		p := (game.dealer + j) % cnt
+		if game.folded[p] {
+			continue
+		}
+		hand := game.hands[p]
+		chosen := game.players[p].Discard(append([]Card(nil), hand...))
+		var discards []Card
+		for _, card := range chosen {
+			if len(discards) < cards && containsCard(hand, card) && !containsCard(discards, card) {
+				discards = append(discards, card)
+			}
+		}
+		for i := len(hand) - 1; len(discards) < cards && i >= 0; i-- {
+			if !containsCard(discards, hand[i]) {
+				discards = append(discards, hand[i])
+			}
+		}
+		kept := make([]Card, 0, len(hand))
+		for _, card := range hand {
+			if !containsCard(discards, card) {
+				kept = append(kept, card)
+			}
+		}
+		game.hands[p] = kept
+		game.deck.Discard(discards...)
+	}
+}
+
+// Award the main pot and any side pots to the best hand(s) eligible for
+// each, splitting them between high and low in hi/lo games. Folded players
+// cannot win. Odd chips go to the first winner to the left of the button.
+func (game *CommunityGame) showdown() {
+	cnt := len(game.players)
+	contenders := make([]Contender, cnt)
+	for p := range game.players {
+		if game.folded[p] {
+			contenders[p] = Contender{Hand: NoHand, Low: NoLow}
+			continue
+		}
+		contenders[p] = game.evaluate(game.hands[p])
+	}
+	game.award(contenders, game.isHiLo())
+}
+
+// Evaluate the given hole cards against the board. A hand that cannot be
+// evaluated can win nothing.
+func (game *CommunityGame) evaluate(hole []Card) Contender {
+	if game.isOmaha() {
+		high, low, err := EvaluateOmaha(hole, game.board, game.isHiLo())
+		if err != nil {
+			return Contender{Hand: NoHand, Low: NoLow}
+		}
+		return Contender{Hand: uint64(high), Low: low}
+	}
+	cards := make([]Card, 0, len(hole)+len(game.board))
+	cards = append(append(cards, hole...), game.board...)
+	high, err := EvaluateHand(cards)
+	if err != nil {
+		return Contender{Hand: NoHand, Low: NoLow}
+	}
+	return Contender{Hand: uint64(high), Low: NoLow}
+}
 
 // Mixed games:
 //	T - Limit 2-7 Triple Draw
@@ -0,0 +1,257 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"errors"
+	"testing"
+)
+
+var _ Game = &CommunityGame{}
+
+func Test_can_play_community_game_with_betting(t *testing.T) {
+	players := []*testPlayer{
+		{chips: 1000, script: []Action{{Type: Check}, {Type: Bet, Amount: 200}}},
+		{chips: 1000, script: []Action{{Type: Call}, {Type: Fold}}},
+		// checking when owing the rest of the small blind is illegal, so
+		// after being asked again twice this folds instead
+		{chips: 1000, script: []Action{{Type: Check}, {Type: Check}, {Type: Check}}},
+	}
+	// seat 1 has the button, so seat 2 is dealt to first
+	deck := stackedDeck(t, "Kc As 7c Kd Ad 2d Ah 9s 5c 3d Jh")
+	game := newCommunityGame(t, players, deck, Holdem, NewBlinds(50, 100))
+	game.Play()
+	expectChips(t, players, 1150, 900, 950)
+	if len(players[2].rejected) != 2 || !errors.Is(players[2].rejected[0], CannotCheck) {
+		t.Fatalf("expected CannotCheck to be reported twice but was %v", players[2].rejected)
+	}
+	// the hand ends on the flop once everyone else has folded
+	if PrintHand(players[1].board) != "(Ah,9s,5c)" {
+		t.Fatalf("expected (Ah,9s,5c) but was %s", PrintHand(players[1].board))
+	}
+	if PrintHand(players[0].hole) != "(As,Ad)" {
+		t.Fatalf("expected (As,Ad) but was %s", PrintHand(players[0].hole))
+	}
+}
+
+func Test_irish_players_discard_after_the_flop(t *testing.T) {
+	// seat 1 throws nothing away, so loses its last two cards, and makes a
+	// set of kings
+	players := []*testPlayer{{chips: 1000, draws: []string{"7c 2h"}}, {chips: 1000}}
+	deck := stackedDeck(t, "As Ks Ad Kd 7c 8c 2h 3h Kc 5d 9s 4c Jh")
+	game := newCommunityGame(t, players, deck, Irish, NewBlinds(50, 100))
+	game.Play()
+	expectChips(t, players, 900, 1100)
+	if PrintHand(game.hands[0]) != "(As,Ad)" || PrintHand(game.hands[1]) != "(Ks,Kd)" {
+		t.Fatalf("expected (As,Ad) and (Ks,Kd) but was %s and %s",
+			PrintHand(game.hands[0]), PrintHand(game.hands[1]))
+	}
+}
+
+func Test_button_posts_small_blind_heads_up(t *testing.T) {
+	players := []*testPlayer{{chips: 1000}, {chips: 1000, script: []Action{{Type: Fold}}}}
+	deck := stackedDeck(t, "Kc As 7c Kd")
+	game := newCommunityGame(t, players, deck, Holdem, NewBlinds(50, 100))
+	game.Play()
+	if game.dealer != 1 || game.smallBlind != 1 || game.bigBlind != 0 {
+		t.Fatalf("expected button and small blind in seat 1 but was %d, %d", game.dealer, game.smallBlind)
+	}
+	// the big blind is dealt to first and the button acts first
+	expectChips(t, players, 1050, 950)
+	if PrintHand(players[0].hole) != "(Kc,7c)" {
+		t.Fatalf("expected (Kc,7c) but was %s", PrintHand(players[0].hole))
+	}
+}
+
+func Test_dead_and_moving_button(t *testing.T) {
+	for _, c := range []struct {
+		rule               int
+		dealer, small, big int
+		chips0, chips3     uint32
+	}{
+		{DeadButton, 2, 3, 0, 900, 850},
+		{MovingButton, 3, 0, 1, 950, 900},
+	} {
+		players := []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}, {chips: 1000}}
+		game := newCommunityGame(t, players, NewPokerDeck(), Holdem, NewBlinds(50, 100))
+		game.SetButtonRule(c.rule)
+		game.init()
+		if game.dealer != 1 || game.smallBlind != 2 || game.bigBlind != 3 {
+			t.Fatalf("expected blinds in seats 2 and 3 but was %d and %d", game.smallBlind, game.bigBlind)
+		}
+		game.RemovePlayer(2)
+		game.init()
+		if game.dealer != c.dealer || game.smallBlind != c.small || game.bigBlind != c.big {
+			t.Fatalf("expected button %d and blinds %d and %d but was %d, %d and %d",
+				c.dealer, c.small, c.big, game.dealer, game.smallBlind, game.bigBlind)
+		}
+		if players[0].chips != c.chips0 || players[3].chips != c.chips3 {
+			t.Fatalf("expected seats 0 and 3 to have %d and %d but was %d and %d",
+				c.chips0, c.chips3, players[0].chips, players[3].chips)
+		}
+	}
+}
+
+func Test_missed_blinds_are_posted_on_return(t *testing.T) {
+	players := []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}, {chips: 1000}}
+	game := newCommunityGame(t, players, NewPokerDeck(), Holdem, NewBlinds(50, 100))
+	game.init()
+	// seat 0 sits out the hand where the big blind would have come to them
+	game.SitOut(0)
+	game.init()
+	if game.bigBlind != 1 || !game.folded[0] {
+		t.Fatalf("expected seat 0 to be skipped but big blind was %d", game.bigBlind)
+	}
+	game.SitIn(0)
+	game.init()
+	// big blind live and small blind dead
+	if game.bets[0] != 100 || game.pots.Contribution(0) != 50 || players[0].chips != 850 {
+		t.Fatalf("expected seat 0 to post 100 live and 50 dead but was %d and %d",
+			game.bets[0], game.pots.Contribution(0))
+	}
+	// nothing more is owed the hand after
+	game.init()
+	if game.bets[0] != 0 || game.pots.Contribution(0) != 0 {
+		t.Fatalf("expected seat 0 to post nothing but was %d", game.bets[0])
+	}
+}
+
+func Test_new_players_post_big_blind(t *testing.T) {
+	players := []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}}
+	game := newCommunityGame(t, players, NewPokerDeck(), Holdem, NewBlinds(50, 100))
+	game.init()
+	joiner := &testPlayer{chips: 1000}
+	if err := game.AddPlayer(joiner); err != nil {
+		t.Fatalf("cannot add player: %v", err)
+	}
+	game.init()
+	if game.bets[3] != 100 || joiner.chips != 900 {
+		t.Fatalf("expected new player to post 100 but was %d", game.bets[3])
+	}
+	for i := 4; i < MaxSeats; i++ {
+		if err := game.AddPlayer(&testPlayer{}); err != nil {
+			t.Fatalf("cannot add player %d: %v", i, err)
+		}
+	}
+	if err := game.AddPlayer(&testPlayer{}); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+}
+
+func Test_community_game_seats_at_most_max_seats(t *testing.T) {
+	players := make([]*testPlayer, MaxSeats+1)
+	for i := range players {
+		players[i] = &testPlayer{chips: 1000}
+	}
+	if _, err := NewCommunityGame(gamePlayers(players), NewPokerDeck(), Holdem, NoLimit, NewBlinds(50, 100), 0); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+	newCommunityGame(t, players[:MaxSeats], NewPokerDeck(), Holdem, NewBlinds(50, 100))
+}
+
+func Test_can_post_antes(t *testing.T) {
+	players := []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}}
+	game := newCommunityGame(t, players, NewPokerDeck(), Holdem, NewBlinds(50, 100).WithAnte(10))
+	game.init()
+	if game.pots.Total() != 30 || game.bets[0] != 100 || game.bets[2] != 50 {
+		t.Fatalf("expected 30 in antes but was %d", game.pots.Total())
+	}
+	expectChips(t, players, 890, 990, 940)
+
+	players = []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}}
+	game = newCommunityGame(t, players, NewPokerDeck(), Holdem, NewBlinds(50, 100).WithBigBlindAnte(100))
+	game.init()
+	if game.pots.Total() != 100 || game.pots.Contribution(0) != 100 {
+		t.Fatalf("expected big blind ante of 100 but was %d", game.pots.Total())
+	}
+	expectChips(t, players, 800, 1000, 950)
+}
+
+func newCommunityGame(t *testing.T, players []*testPlayer, deck Deck, variant GameType, blinds Blinds) *CommunityGame {
+	game, err := NewCommunityGame(gamePlayers(players), deck, variant, NoLimit, blinds, 0)
+	if err != nil {
+		t.Fatalf("cannot create community game: %v", err)
+	}
+	return game
+}
+
+// Player that takes scripted actions, then checks or calls. In draw games it
+// throws away the scripted cards for each draw, then stands pat. Actions the
+// round rejects are recorded.
+type testPlayer struct {
+	chips    uint32
+	won      uint32
+	hole     []Card
+	board    []Card
+	script   []Action
+	draws    []string
+	rejected []error
+	acts     int
+}
+
+func (p *testPlayer) DealPrivate(card Card)   { p.hole = append(p.hole, card) }
+func (p *testPlayer) DealShared(cards []Card) { p.board = append(p.board, cards...) }
+func (p *testPlayer) DealPublic(card Card)    { p.hole = append(p.hole, card) }
+func (p *testPlayer) Win(amount uint32)       { p.won += amount; p.chips += amount }
+func (p *testPlayer) Chips() uint32           { return p.chips }
+func (p *testPlayer) Pay(amount uint32)       { p.chips -= amount }
+
+func (p *testPlayer) Act(round *BettingRound) Action {
+	p.acts++
+	if err := round.Rejected(); err != nil {
+		p.rejected = append(p.rejected, err)
+	}
+	if len(p.script) > 0 {
+		action := p.script[0]
+		p.script = p.script[1:]
+		return action
+	}
+	if round.ToCall(round.Actor()) > 0 {
+		return Action{Type: Call}
+	}
+	return Action{Type: Check}
+}
+
+func (p *testPlayer) Discard(hand []Card) []Card {
+	if len(p.draws) == 0 {
+		return nil
+	}
+	discards, _ := ParseHand(p.draws[0])
+	p.draws = p.draws[1:]
+	kept := p.hole[:0]
+	for _, card := range p.hole {
+		if !containsCard(discards, card) {
+			kept = append(kept, card)
+		}
+	}
+	p.hole = kept
+	return discards
+}
+
+func expectChips(t *testing.T, players []*testPlayer, chips ...uint32) {
+	for i, expected := range chips {
+		if players[i].chips != expected {
+			t.Fatalf("expected seat %d to have %d chips but was %d", i, expected, players[i].chips)
+		}
+	}
+}
+
+func gamePlayers(players []*testPlayer) []Player {
+	rv := make([]Player, len(players))
+	for i, p := range players {
+		rv[i] = p
+	}
+	return rv
+}
+
+func stackedDeck(t *testing.T, cards string) *PokerDeck {
+	hand, err := ParseHand(cards)
+	if err != nil {
+		t.Fatalf("cannot parse %q: %v", cards, err)
+	}
+	deck, err := NewStackedDeck(hand)
+	if err != nil {
+		t.Fatalf("cannot stack deck: %v", err)
+	}
+	return deck
+}
@@ -4,7 +4,6 @@ package poker
 
 import (
 	"fmt"
-	"strings"
 )
 
 const CardsPerDeck = 52
@@ -47,6 +46,10 @@ const (
 	Ace   = 12
 )
 
+// The joker, or bug. It has no rank of its own, and only the evaluators that
+// say so accept it.
+const Joker Card = Club | Diamond | Heart | Spade
+
 var rankStr = []string{"2", "3", "4", "5", "6", "7", "8", "9", "T", "J", "Q", "K", "A"}
 
 // ----- CARD API ------------------------------------------------------------
@@ -83,6 +86,10 @@ func (card Card) Suit() int {
 func (card Card) String() string {
 	var suit string
 
+	if card == Joker {
+		return "Jk"
+	}
+
 	switch card.Suit() {
 	case Club:
 		suit = "c"
@@ -98,23 +105,40 @@ func (card Card) String() string {
 
 // ----- DECK API ------------------------------------------------------------
 
-var EmptyDeck = fmt.Errorf("deck is empty")
+var (
+	EmptyDeck     = fmt.Errorf("deck is empty")
+	CardNotInDeck = fmt.Errorf("card is not in the deck")
+	CardNotDealt  = fmt.Errorf("card has not been dealt from the deck")
+)
 
 type Deck interface {
 	Shuffle()
-	Deal() Card
+	Deal() (Card, error)
+	MustDeal() Card
+	Burn() error
+	Peek() (Card, error)
+	Remove(cards ...Card) error
+	Discard(cards ...Card) error
+	ReshuffleDiscards()
 	Empty() bool
 	Remaining() int
 }
 
+// Cards in positions before pos have been dealt, burnt or removed; the rest
+// make up the stub still to be dealt. order holds the cards in the order the
+// deck was created in, which every shuffle starts from.
 type PokerDeck struct {
-	cards []Card
-	pos   int
+	cards    []Card
+	pos      int
+	discards []Card
+	source   RandomSource
+	order    []Card
+	stacked  bool
 }
 
 // Create a new deck of cards. This deck will *NOT* be shuffled.
 func NewPokerDeck() *PokerDeck {
-	deck := &PokerDeck{cards: make([]Card, CardsPerDeck)}
+	deck := &PokerDeck{cards: make([]Card, CardsPerDeck), source: CryptoSource}
 	n := 0
 	for suit := Club; suit >= Spade; suit >>= 1 {
 		for rank := Deuce; rank <= Ace; rank++ {
@@ -122,30 +146,183 @@ func NewPokerDeck() *PokerDeck {
 			n++
 		}
 	}
+	deck.order = append([]Card(nil), deck.cards...)
 	return deck
 }
 
-// Shuffle this deck of cards. We use a Fisher-Yates shuffle:
-// http://en.wikipedia.org/wiki/Fisher-Yates_shuffle
-func (deck *PokerDeck) Shuffle() {
-	for i := len(deck.cards) - 1; i > 0; i-- {
-		if j := randInt(i + 1); j >= 0 && i != j {
-			deck.cards[i], deck.cards[j] = deck.cards[j], deck.cards[i]
+// Create a new deck holding only the cards from the given rank up to the ace.
+// (e.g. NewStrippedDeck(Seven) for a 32-card deck) This deck will *NOT* be
+// shuffled.
+func NewStrippedDeck(lowest int) *PokerDeck {
+	deck := &PokerDeck{source: CryptoSource}
+	for suit := Club; suit >= Spade; suit >>= 1 {
+		for rank := lowest; rank <= Ace; rank++ {
+			deck.cards = append(deck.cards, NewCard(rank, suit))
+		}
+	}
+	deck.order = append([]Card(nil), deck.cards...)
+	return deck
+}
+
+// Create a new 36-card deck for Short Deck (6+) Hold'em, with the deuces
+// through fives taken out. This deck will *NOT* be shuffled.
+func NewShortDeck() *PokerDeck {
+	return NewStrippedDeck(Six)
+}
+
+// Create a new 53-card deck with the joker, as used for Ace-to-Five draw.
+// This deck will *NOT* be shuffled.
+func NewPokerDeckWithJoker() *PokerDeck {
+	deck := NewPokerDeck()
+	deck.cards = append(deck.cards, Joker)
+	deck.order = append(deck.order, Joker)
+	return deck
+}
+
+// Create a new deck of cards shuffled by the given source of randomness
+// rather than the default CryptoSource.
+func NewPokerDeckWithSource(source RandomSource) *PokerDeck {
+	deck := NewPokerDeck()
+	deck.source = source
+	return deck
+}
+
+// Create a deck that deals the given cards in the given order, first card
+// first. Shuffling a stacked deck only restores that order, and reshuffled
+// discards go under the stub in the order they were discarded, so a game
+// can be played through with a known deal. Returns DuplicateCard if a card
+// is given more than once.
+func NewStackedDeck(cards []Card) (*PokerDeck, error) {
+	seen := make(map[Card]bool)
+	for _, card := range cards {
+		if seen[card] {
+			return nil, DuplicateCard
 		}
+		seen[card] = true
 	}
+	deck := &PokerDeck{cards: make([]Card, len(cards)), source: CryptoSource}
+	deck.order = append([]Card(nil), cards...)
+	deck.stacked = true
+	copy(deck.cards, cards)
+	return deck, nil
+}
+
+// Change the source of randomness used to shuffle this deck.
+func (deck *PokerDeck) SetSource(source RandomSource) {
+	deck.source = source
+}
+
+// Gather up every card and shuffle this deck. We use a Fisher-Yates shuffle:
+// http://en.wikipedia.org/wiki/Fisher-Yates_shuffle
+// The cards are put back in the order the deck was created in first, so the
+// same source always gives the same deck however often the deck is reused.
+func (deck *PokerDeck) Shuffle() {
 	deck.pos = 0
+	deck.discards = deck.discards[:0]
+	copy(deck.cards, deck.order)
+	if !deck.stacked {
+		deck.shuffle(deck.cards)
+	}
 }
 
-// Deal one card from the deck. Returns error on empty deck.
-func (deck *PokerDeck) Deal() Card {
+// Deal one card from the deck. Returns EmptyDeck if there are no cards left.
+func (deck *PokerDeck) Deal() (Card, error) {
 	if deck.Empty() {
-		panic(EmptyDeck)
+		return 0, EmptyDeck
 	}
 	card := deck.cards[deck.pos]
 	deck.pos++
+	return card, nil
+}
+
+// Deal one card from the deck. Panics with EmptyDeck if there are no cards
+// left, so only use this where running out of cards is a bug.
+func (deck *PokerDeck) MustDeal() Card {
+	card, err := deck.Deal()
+	if err != nil {
+		panic(err)
+	}
 	return card
 }
 
+// Deal the top card of the deck face down to nobody. Burnt cards are not
+// reshuffled with the discards. Returns EmptyDeck if there are no cards left.
+func (deck *PokerDeck) Burn() error {
+	_, err := deck.Deal()
+	return err
+}
+
+// Report the top card of the deck without dealing it. Returns EmptyDeck if
+// there are no cards left.
+func (deck *PokerDeck) Peek() (Card, error) {
+	if deck.Empty() {
+		return 0, EmptyDeck
+	}
+	return deck.cards[deck.pos], nil
+}
+
+// Take known dead cards out of the stub so they cannot be dealt. The rest of
+// the stub keeps its order. Returns CardNotInDeck, and removes nothing, if
+// any of the cards is not in the stub, or DuplicateCard if a card is given
+// more than once.
+func (deck *PokerDeck) Remove(cards ...Card) error {
+	for i, card := range cards {
+		if deck.find(card, deck.pos, len(deck.cards)) < 0 {
+			return CardNotInDeck
+		}
+		if containsCard(cards[:i], card) {
+			return DuplicateCard
+		}
+	}
+	for _, card := range cards {
+		// slide the stub above the card down over it, which leaves the card
+		// just past the dealt cards
+		k := deck.find(card, deck.pos, len(deck.cards))
+		copy(deck.cards[deck.pos+1:k+1], deck.cards[deck.pos:k])
+		deck.cards[deck.pos] = card
+		deck.pos++
+	}
+	return nil
+}
+
+// Put dealt cards on the discard pile, so they can be reshuffled into the
+// stub later. Returns CardNotDealt, and discards nothing, if any of the cards
+// has not been dealt or is already discarded.
+func (deck *PokerDeck) Discard(cards ...Card) error {
+	for i, card := range cards {
+		if deck.find(card, 0, deck.pos) < 0 {
+			return CardNotDealt
+		}
+		if containsCard(deck.discards, card) || containsCard(cards[:i], card) {
+			return CardNotDealt
+		}
+	}
+	deck.discards = append(deck.discards, cards...)
+	return nil
+}
+
+// Shuffle the discard pile together with whatever is left of the stub to
+// make a new stub. This is for draw games that run out of cards.
+func (deck *PokerDeck) ReshuffleDiscards() {
+	for _, card := range deck.discards {
+		// move the card to the end of the dealt cards and back into the stub
+		k := deck.find(card, 0, deck.pos)
+		copy(deck.cards[k:deck.pos-1], deck.cards[k+1:deck.pos])
+		deck.pos--
+		deck.cards[deck.pos] = card
+	}
+	if deck.stacked {
+		// keep the stub on top and the discards under it, in discard order
+		n := len(deck.discards)
+		stub := append([]Card(nil), deck.cards[deck.pos+n:]...)
+		copy(deck.cards[deck.pos:], stub)
+		copy(deck.cards[len(deck.cards)-n:], deck.discards)
+	} else {
+		deck.shuffle(deck.cards[deck.pos:])
+	}
+	deck.discards = deck.discards[:0]
+}
+
 // Is this deck empty?
 func (deck *PokerDeck) Empty() bool {
 	return deck.Remaining() == 0
@@ -153,23 +330,129 @@ func (deck *PokerDeck) Empty() bool {
 
 // How many cards are remaining in this deck?
 func (deck *PokerDeck) Remaining() int {
-	return (len(deck.cards) - deck.pos) - 1
+	return len(deck.cards) - deck.pos
+}
+
+// Shuffle the given cards in place with this deck's source of randomness.
+func (deck *PokerDeck) shuffle(cards []Card) {
+	for i := len(cards) - 1; i > 0; i-- {
+		if j := deck.source.Intn(i + 1); j >= 0 && i != j {
+			cards[i], cards[j] = cards[j], cards[i]
+		}
+	}
+}
+
+// Find the position of the given card among cards[from:to], or -1.
+func (deck *PokerDeck) find(card Card, from, to int) int {
+	for i := from; i < to; i++ {
+		if deck.cards[i] == card {
+			return i
+		}
+	}
+	return -1
+}
+
+// Is the given card among the cards?
+func containsCard(cards []Card, card Card) bool {
+	for _, other := range cards {
+		if other == card {
+			return true
+		}
+	}
+	return false
 }
 
 // ----- PUBLIC HAND EVALUATION API ------------------------------------------
 
+var InvalidHandSize = fmt.Errorf("hand must contain between 5 and 7 cards")
+
+// Equivalence value of a hand for high. Values run from 1 (royal flush) to
+// 7462 (7-5-4-3-2 offsuit); the lower the value, the stronger the hand. Two
+// hands with the same value are tied.
+type HandValue uint16
+
+// Report the ranking of this value. (i.e. StraightFlush, ThreeOfAKind, etc)
+func (val HandValue) Rank() int {
+	return handRank(uint16(val))
+}
+
+// Does this value beat the other one?
+func (val HandValue) Beats(other HandValue) bool {
+	return val < other
+}
+
 // Determine the given hand's ranking. (i.e. StraightFlush, ThreeOfAKind, etc)
-func EvaluateForHigh(hand []Card) int {
-	return handRank(eval7CardHand(hand))
+// Returns InvalidHandSize unless the hand contains 5, 6 or 7 cards, and
+// InvalidCard if it holds the Joker.
+func EvaluateForHigh(hand []Card) (int, error) {
+	val, err := EvaluateHand(hand)
+	if err != nil {
+		return 0, err
+	}
+	return val.Rank(), nil
+}
+
+// Determine the given hand's equivalence value. Returns InvalidHandSize
+// unless the hand contains 5, 6 or 7 cards, and InvalidCard if it holds the
+// Joker.
+func EvaluateHand(hand []Card) (HandValue, error) {
+	if len(hand) < 5 || len(hand) > 7 {
+		return 0, InvalidHandSize
+	}
+	if containsCard(hand, Joker) {
+		return 0, InvalidCard
+	}
+	return HandValue(evalHand(hand)), nil
+}
+
+// Result of evaluating a hand for high: its equivalence value and the five
+// cards that make it, most important first. (i.e. the pair before the
+// kickers, the highest card of a straight first, etc)
+type Evaluation struct {
+	Value HandValue
+	Cards []Card
+}
+
+// Determine the given hand's equivalence value and which five of its cards
+// make that value. Returns InvalidHandSize unless the hand contains 5, 6 or
+// 7 cards, and InvalidCard if it holds the Joker.
+func Evaluate(hand []Card) (Evaluation, error) {
+	if len(hand) < 5 || len(hand) > 7 {
+		return Evaluation{}, InvalidHandSize
+	}
+	if containsCard(hand, Joker) {
+		return Evaluation{}, InvalidCard
+	}
+	val, best := evalBestFive(hand)
+	return Evaluation{
+		Value: HandValue(val),
+		Cards: orderBySignificance(HandValue(val), best[:]),
+	}, nil
+}
+
+// Compare two hands for high. Returns 1 if a beats b, -1 if b beats a and 0
+// if the hands are tied.
+func CompareHands(a, b []Card) (int, error) {
+	va, err := EvaluateHand(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := EvaluateHand(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case va.Beats(vb):
+		return 1, nil
+	case vb.Beats(va):
+		return -1, nil
+	}
+	return 0, nil
 }
 
 // Return string representation of hand of cards.
 func PrintHand(hand []Card) string {
-	rv := make([]string, len(hand))
-	for i, card := range hand {
-		rv[i] = card.String()
-	}
-	return "(" + strings.Join(rv, ",") + ")"
+	return FormatHand(hand, ASCIIStyle)
 }
 
 // ----- HAND EVALUATION FUNCTIONS -------------------------------------------
@@ -203,23 +486,70 @@ func handRank(val uint16) int {
 	return StraightFlush
 }
 
+// Generate the equivalence value for a 5, 6 or 7 card hand. Callers must
+// have checked the size of the hand already.
+func evalHand(hand []Card) uint16 {
+	switch len(hand) {
+	case 5:
+		return eval5CardHand(hand)
+	case 6:
+		return eval6CardHand(hand)
+	}
+	return eval7(hand)
+}
+
+// Generate the equivalence value for a 5, 6 or 7 card hand along with the
+// five cards that make it. Callers must have checked the size of the hand.
+func evalBestFive(hand []Card) (uint16, [5]Card) {
+	var five [5]Card
+	if len(hand) == 5 {
+		copy(five[:], hand)
+		return eval5CardHand(hand), five
+	}
+	perms := perm7[:]
+	if len(hand) == 6 {
+		perms = perm6[:]
+	}
+	best, winner := evalSubhands(hand, perms)
+	for j := 0; j < 5; j++ {
+		five[j] = hand[perms[winner][j]]
+	}
+	return best, five
+}
+
 // Generate the equivalence value for a 7-card hand. This is unoptimized, as
 // it will evaluate all possible 5-card hands in a 7-card hand (7-choose-5,
 // or 21) and return the best equivalence value found.
 func eval7CardHand(hand []Card) uint16 {
+	best, _ := evalSubhands(hand, perm7[:])
+	return best
+}
+
+// Generate the equivalence value for a 6-card hand by evaluating each of the
+// 6 possible 5-card hands in it.
+func eval6CardHand(hand []Card) uint16 {
+	best, _ := evalSubhands(hand, perm6[:])
+	return best
+}
+
+// Evaluate the 5-card subsets of hand given by perms and return the best
+// equivalence value found, along with the index of the subset that made it.
+func evalSubhands(hand []Card, perms [][5]uint32) (uint16, int) {
 	var best uint16 = 0xFFFF
+	winner := 0
 
 	subhand := []Card{0, 0, 0, 0, 0}
-	for i := 0; i < 21; i++ {
+	for i, perm := range perms {
 		for j := 0; j < 5; j++ {
-			subhand[j] = hand[perm7[i][j]]
+			subhand[j] = hand[perm[j]]
 		}
 		q := eval5CardHand(subhand)
 		if q < best {
 			best = q
+			winner = i
 		}
 	}
-	return best
+	return best, winner
 }
 
 // Generate the equivalence value for a 5-card hand.
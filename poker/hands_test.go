@@ -9,7 +9,7 @@ import (
 func Test_can_detect_royal_flush(t *testing.T) {
 	// ace-high straight flush in diamonds
 	hand := makeHand([]string{"Td", "Kd", "7s", "Jd", "Ad", "3c", "Qd"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != StraightFlush {
 		t.Fatalf("expected %d but was %d", StraightFlush, rank)
 	}
@@ -18,7 +18,7 @@ func Test_can_detect_royal_flush(t *testing.T) {
 func Test_can_detect_straight_flush(t *testing.T) {
 	// ten-high straight flush in spades
 	hand := makeHand([]string{"Ts", "9s", "7s", "Js", "Ad", "3c", "8s"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != StraightFlush {
 		t.Fatalf("expected %d but was %d", StraightFlush, rank)
 	}
@@ -28,13 +28,13 @@ func Test_can_detect_steal_wheel(t *testing.T) {
 	// steal wheel (A-2-3-4-5 of same suit) in clubs
 	// NB: we put an off-suit ace first here to try and trick the hand evaluator
 	hand := makeHand([]string{"Ad", "Ac", "2c", "4c", "Kd", "3c", "5c"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != StraightFlush {
 		t.Fatalf("expected %d but was %d", StraightFlush, rank)
 	}
 	// same hand with in-suit ace first to ensure we can catch it both ways
 	hand = makeHand([]string{"Ac", "Ad", "2c", "4c", "Kd", "3c", "5c"})
-	rank = EvaluateForHigh(hand)
+	rank = highRank(t, hand)
 	if rank != StraightFlush {
 		t.Fatalf("expected %d but was %d", StraightFlush, rank)
 	}
@@ -43,7 +43,7 @@ func Test_can_detect_steal_wheel(t *testing.T) {
 func Test_can_detect_four_of_a_kind(t *testing.T) {
 	// four tens
 	hand := makeHand([]string{"Ts", "Tc", "8h", "7s", "Td", "Kd", "Th"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != FourOfAKind {
 		t.Fatalf("expected %d but was %d", FourOfAKind, rank)
 	}
@@ -52,7 +52,7 @@ func Test_can_detect_four_of_a_kind(t *testing.T) {
 func Test_can_detect_full_house(t *testing.T) {
 	// tens full of kings
 	hand := makeHand([]string{"Ts", "Tc", "8h", "7s", "Td", "Kd", "Kh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != FullHouse {
 		t.Fatalf("expected %d but was %d", FullHouse, rank)
 	}
@@ -61,7 +61,7 @@ func Test_can_detect_full_house(t *testing.T) {
 func Test_can_detect_full_house_in_two_sets(t *testing.T) {
 	// kings full of tens
 	hand := makeHand([]string{"Ts", "Tc", "8h", "Ks", "Td", "Kd", "Kh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != FullHouse {
 		t.Fatalf("expected %d but was %d", FullHouse, rank)
 	}
@@ -70,7 +70,7 @@ func Test_can_detect_full_house_in_two_sets(t *testing.T) {
 func Test_can_detect_flush(t *testing.T) {
 	// king-high flush in spades
 	hand := makeHand([]string{"Ts", "9s", "8h", "Ks", "7s", "2s", "Kh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != Flush {
 		t.Fatalf("expected %d but was %d", Flush, rank)
 	}
@@ -79,7 +79,7 @@ func Test_can_detect_flush(t *testing.T) {
 func Test_can_detect_straight(t *testing.T) {
 	// jack-high straight
 	hand := makeHand([]string{"Ts", "9s", "8h", "Ks", "7s", "3d", "Jh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != Straight {
 		t.Fatalf("expected %d but was %d", Straight, rank)
 	}
@@ -88,7 +88,7 @@ func Test_can_detect_straight(t *testing.T) {
 func Test_can_detect_set(t *testing.T) {
 	// set of nines
 	hand := makeHand([]string{"Ts", "9s", "8h", "9d", "9c", "3d", "Jh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != ThreeOfAKind {
 		t.Fatalf("expected %d but was %d", ThreeOfAKind, rank)
 	}
@@ -97,7 +97,7 @@ func Test_can_detect_set(t *testing.T) {
 func Test_can_detect_two_pair(t *testing.T) {
 	// two-pair, tens and sevens
 	hand := makeHand([]string{"Ts", "Td", "8h", "Ks", "7s", "3d", "7h"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != TwoPair {
 		t.Fatalf("expected %d but was %d", TwoPair, rank)
 	}
@@ -106,7 +106,7 @@ func Test_can_detect_two_pair(t *testing.T) {
 func Test_can_detect_one_pair(t *testing.T) {
 	// pair of nines
 	hand := makeHand([]string{"Ts", "9s", "8h", "9c", "4c", "3d", "Jh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != OnePair {
 		t.Fatalf("expected %d but was %d", OnePair, rank)
 	}
@@ -115,60 +115,301 @@ func Test_can_detect_one_pair(t *testing.T) {
 func Test_can_detect_high_card(t *testing.T) {
 	// king high
 	hand := makeHand([]string{"Ts", "9s", "8h", "Ks", "4s", "3d", "Jh"})
-	rank := EvaluateForHigh(hand)
+	rank := highRank(t, hand)
 	if rank != HighCard {
 		t.Fatalf("expected %d but was %d", HighCard, rank)
 	}
 }
 
+func Test_can_compare_hands_of_same_rank(t *testing.T) {
+	// ace-high flush beats king-high flush
+	a := makeHand([]string{"As", "9s", "8h", "4s", "7s", "2s", "Kh"})
+	b := makeHand([]string{"Ks", "9s", "8h", "Qs", "7s", "2s", "Kh"})
+	if cmp := compareHands(t, a, b); cmp != 1 {
+		t.Fatalf("expected 1 but was %d", cmp)
+	}
+	if cmp := compareHands(t, b, a); cmp != -1 {
+		t.Fatalf("expected -1 but was %d", cmp)
+	}
+	// kings and fives beats kings and fours
+	a = makeHand([]string{"Ks", "Kd", "5h", "5s", "2c", "3d", "8h"})
+	b = makeHand([]string{"Ks", "Kd", "4h", "4s", "Ac", "3d", "8h"})
+	if cmp := compareHands(t, a, b); cmp != 1 {
+		t.Fatalf("expected 1 but was %d", cmp)
+	}
+}
+
+func Test_can_detect_tied_hands(t *testing.T) {
+	// both players play the broadway straight on the board
+	a := makeHand([]string{"2c", "3d", "Ts", "Jd", "Qh", "Kc", "Ad"})
+	b := makeHand([]string{"4c", "4d", "Ts", "Jd", "Qh", "Kc", "Ad"})
+	if cmp := compareHands(t, a, b); cmp != 0 {
+		t.Fatalf("expected 0 but was %d", cmp)
+	}
+}
+
+func Test_can_evaluate_five_and_six_card_hands(t *testing.T) {
+	five := makeHand([]string{"Ts", "Tc", "8h", "8s", "Td"})
+	if rank := highRank(t, five); rank != FullHouse {
+		t.Fatalf("expected %d but was %d", FullHouse, rank)
+	}
+	six := makeHand([]string{"Ts", "9s", "8h", "Js", "7s", "3c"})
+	if rank := highRank(t, six); rank != Straight {
+		t.Fatalf("expected %d but was %d", Straight, rank)
+	}
+	// the trey does not play, so the first five cards make the same straight
+	if compareHands(t, six, six[:5]) != 0 {
+		t.Fatalf("expected six-card hand to play its jack-high straight")
+	}
+}
+
+func Test_evaluation_reports_best_five_cards(t *testing.T) {
+	hand := makeHand([]string{"Ts", "9s", "8h", "Ks", "7s", "2s", "Kh"})
+	eval, err := Evaluate(hand)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	if PrintHand(eval.Cards) != "(Ks,Ts,9s,7s,2s)" {
+		t.Fatalf("expected (Ks,Ts,9s,7s,2s) but was %s", PrintHand(eval.Cards))
+	}
+	if val, _ := EvaluateHand(hand); eval.Value != val {
+		t.Fatalf("expected %d but was %d", val, eval.Value)
+	}
+	// six cards plays the straight rather than the pair
+	hand = makeHand([]string{"Ts", "9d", "8h", "Jc", "7s", "Td"})
+	eval, err = Evaluate(hand)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	if PrintHand(eval.Cards) != "(Jc,Ts,9d,8h,7s)" {
+		t.Fatalf("expected (Jc,Ts,9d,8h,7s) but was %s", PrintHand(eval.Cards))
+	}
+}
+
+func Test_rejects_hands_of_wrong_size(t *testing.T) {
+	deck := NewPokerDeck()
+	for _, n := range []int{0, 1, 4, 8, 9} {
+		hand := deck.cards[:n]
+		if _, err := EvaluateForHigh(hand); err != InvalidHandSize {
+			t.Fatalf("expected InvalidHandSize for %d cards but was %v", n, err)
+		}
+		if _, err := CompareHands(hand, deck.cards[:7]); err != InvalidHandSize {
+			t.Fatalf("expected InvalidHandSize for %d cards but was %v", n, err)
+		}
+	}
+}
+
+func Test_high_evaluators_reject_joker(t *testing.T) {
+	hand := makeHand([]string{"Jk", "Ah", "Kh", "Qh", "Jh"})
+	if _, err := EvaluateHand(hand); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+	if _, err := EvaluateForHigh(hand); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+	if _, err := Evaluate(hand); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+}
+
+func Test_seeded_decks_shuffle_reproducibly(t *testing.T) {
+	a := NewPokerDeckWithSource(NewSeededSource(42))
+	b := NewPokerDeck()
+	b.SetSource(NewSeededSource(42))
+	a.Shuffle()
+	b.Shuffle()
+	if PrintHand(a.cards) != PrintHand(b.cards) {
+		t.Fatalf("expected %s but was %s", PrintHand(a.cards), PrintHand(b.cards))
+	}
+	if PrintHand(a.cards) == PrintHand(NewPokerDeck().cards) {
+		t.Fatalf("expected deck to be shuffled")
+	}
+	// a different seed gives a different deal
+	c := NewPokerDeckWithSource(NewSeededSource(43))
+	c.Shuffle()
+	if PrintHand(a.cards) == PrintHand(c.cards) {
+		t.Fatalf("expected seeds 42 and 43 to shuffle differently")
+	}
+}
+
+func Test_stacked_deck_deals_in_order(t *testing.T) {
+	cards := makeHand([]string{"As", "Kd", "7c", "2h"})
+	deck, err := NewStackedDeck(cards)
+	if err != nil {
+		t.Fatalf("cannot stack deck: %v", err)
+	}
+	for round := 0; round < 2; round++ {
+		deck.Shuffle()
+		for _, want := range cards {
+			if card := deck.MustDeal(); card != want {
+				t.Fatalf("expected %s but was %s", want, card)
+			}
+		}
+		if !deck.Empty() {
+			t.Fatalf("expected deck to be empty but %d cards remain", deck.Remaining())
+		}
+	}
+	if _, err := NewStackedDeck(makeHand([]string{"As", "Kd", "As"})); err != DuplicateCard {
+		t.Fatalf("expected DuplicateCard but was %v", err)
+	}
+}
+
+func Test_can_deal_every_card(t *testing.T) {
+	deck := NewPokerDeck()
+	deck.Shuffle()
+	seen := make(map[Card]bool)
+	for i := CardsPerDeck; i > 0; i-- {
+		if deck.Remaining() != i {
+			t.Fatalf("expected %d cards remaining but was %d", i, deck.Remaining())
+		}
+		card, err := deck.Deal()
+		if err != nil {
+			t.Fatalf("cannot deal card %d: %v", CardsPerDeck-i+1, err)
+		}
+		seen[card] = true
+	}
+	if len(seen) != CardsPerDeck || !deck.Empty() {
+		t.Fatalf("expected %d distinct cards and empty deck but was %d", CardsPerDeck, len(seen))
+	}
+	if _, err := deck.Deal(); err != EmptyDeck {
+		t.Fatalf("expected EmptyDeck but was %v", err)
+	}
+	if _, err := deck.Peek(); err != EmptyDeck {
+		t.Fatalf("expected EmptyDeck but was %v", err)
+	}
+	if err := deck.Burn(); err != EmptyDeck {
+		t.Fatalf("expected EmptyDeck but was %v", err)
+	}
+	defer func() {
+		if recover() != EmptyDeck {
+			t.Fatalf("expected MustDeal to panic with EmptyDeck")
+		}
+	}()
+	deck.MustDeal()
+}
+
+func Test_can_peek_burn_and_remove(t *testing.T) {
+	deck, _ := NewStackedDeck(makeHand([]string{"As", "Kd", "7c", "2h", "9s", "Td"}))
+	if card, _ := deck.Peek(); card.String() != "As" || deck.Remaining() != 6 {
+		t.Fatalf("expected to peek As with 6 remaining but was %s with %d", card, deck.Remaining())
+	}
+	deck.Burn()
+	if err := deck.Remove(makeHand([]string{"2h", "As"})...); err != CardNotInDeck {
+		t.Fatalf("expected CardNotInDeck but was %v", err)
+	}
+	if err := deck.Remove(makeHand([]string{"2h", "2h"})...); err != DuplicateCard {
+		t.Fatalf("expected DuplicateCard but was %v", err)
+	}
+	if err := deck.Remove(makeHand([]string{"2h", "7c"})...); err != nil {
+		t.Fatalf("cannot remove cards: %v", err)
+	}
+	if deck.Remaining() != 3 {
+		t.Fatalf("expected 3 cards remaining but was %d", deck.Remaining())
+	}
+	for _, want := range []string{"Kd", "9s", "Td"} {
+		if card := deck.MustDeal(); card.String() != want {
+			t.Fatalf("expected %s but was %s", want, card)
+		}
+	}
+	// shuffling a stacked deck puts the removed cards back
+	deck.Shuffle()
+	if deck.Remaining() != 6 || deck.MustDeal().String() != "As" {
+		t.Fatalf("expected full stacked deck after shuffle")
+	}
+}
+
+func Test_can_reshuffle_discards(t *testing.T) {
+	deck, _ := NewStackedDeck(makeHand([]string{"As", "Kd", "7c", "2h", "9s", "Td"}))
+	hand := []Card{deck.MustDeal(), deck.MustDeal(), deck.MustDeal(), deck.MustDeal()}
+	if err := deck.Discard(deck.cards[5]); err != CardNotDealt {
+		t.Fatalf("expected CardNotDealt but was %v", err)
+	}
+	if err := deck.Discard(hand[1], hand[2]); err != nil {
+		t.Fatalf("cannot discard: %v", err)
+	}
+	if err := deck.Discard(hand[1]); err != CardNotDealt {
+		t.Fatalf("expected CardNotDealt but was %v", err)
+	}
+	deck.ReshuffleDiscards()
+	// stacked decks put the discards under what was left of the stub
+	var dealt []Card
+	for !deck.Empty() {
+		dealt = append(dealt, deck.MustDeal())
+	}
+	if PrintHand(dealt) != "(9s,Td,Kd,7c)" {
+		t.Fatalf("expected (9s,Td,Kd,7c) but was %s", PrintHand(dealt))
+	}
+
+	// a shuffled deck deals the discards again in some order
+	shuffled := NewPokerDeckWithSource(NewSeededSource(7))
+	shuffled.Shuffle()
+	var held []Card
+	for i := 0; i < 50; i++ {
+		held = append(held, shuffled.MustDeal())
+	}
+	shuffled.Discard(held[:5]...)
+	shuffled.ReshuffleDiscards()
+	if shuffled.Remaining() != 7 {
+		t.Fatalf("expected 7 cards remaining but was %d", shuffled.Remaining())
+	}
+	stub := make(map[Card]bool)
+	for !shuffled.Empty() {
+		stub[shuffled.MustDeal()] = true
+	}
+	for _, card := range held[:5] {
+		if !stub[card] {
+			t.Fatalf("expected %s to be dealt again", card)
+		}
+	}
+}
+
+func Test_can_build_alternative_decks(t *testing.T) {
+	for _, c := range []struct {
+		deck   *PokerDeck
+		size   int
+		lowest string
+	}{
+		{NewShortDeck(), 36, "6c"},
+		{NewStrippedDeck(Seven), 32, "7c"},
+		{NewPokerDeckWithJoker(), 53, "2c"},
+	} {
+		if c.deck.Remaining() != c.size {
+			t.Fatalf("expected %d cards but was %d", c.size, c.deck.Remaining())
+		}
+		if card, _ := c.deck.Peek(); card.String() != c.lowest {
+			t.Fatalf("expected %s on top but was %s", c.lowest, card)
+		}
+	}
+	deck := NewPokerDeckWithJoker()
+	if last := deck.cards[52]; last != Joker || last.String() != "Jk" {
+		t.Fatalf("expected joker but was %s", last)
+	}
+}
+
+func highRank(t *testing.T, hand []Card) int {
+	rank, err := EvaluateForHigh(hand)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	return rank
+}
+
+func compareHands(t *testing.T, a, b []Card) int {
+	cmp, err := CompareHands(a, b)
+	if err != nil {
+		t.Fatalf("cannot compare %s to %s: %v", PrintHand(a), PrintHand(b), err)
+	}
+	return cmp
+}
+
 func makeHand(cards []string) []Card {
 	hand := make([]Card, len(cards))
 	for i, str := range cards {
-		hand[i] = cardFromString(str)
+		card, err := ParseCard(str)
+		if err != nil {
+			panic(err)
+		}
+		hand[i] = card
 	}
 	return hand
 }
-
-func cardFromString(s string) Card {
-	var rank, suit int
-
-	switch s[0] {
-	case '2':
-		rank = Deuce
-	case '3':
-		rank = Trey
-	case '4':
-		rank = Four
-	case '5':
-		rank = Five
-	case '6':
-		rank = Six
-	case '7':
-		rank = Seven
-	case '8':
-		rank = Eight
-	case '9':
-		rank = Nine
-	case 'T':
-		rank = Ten
-	case 'J':
-		rank = Jack
-	case 'Q':
-		rank = Queen
-	case 'K':
-		rank = King
-	case 'A':
-		rank = Ace
-	}
-	switch s[1] {
-	case 'c':
-		suit = Club
-	case 'd':
-		suit = Diamond
-	case 'h':
-		suit = Heart
-	case 's':
-		suit = Spade
-	}
-	return NewCard(rank, suit)
-}
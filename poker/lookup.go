@@ -0,0 +1,162 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"sync"
+)
+
+// Strategies for evaluating 7-card hands.
+const (
+	// Evaluate all 21 5-card subsets of the hand. This needs no tables
+	// beyond the 5-card ones, but is slow.
+	SubsetEvaluator = iota
+	// Look the hand up in tables precomputed from the 5-card evaluator: one
+	// indexed by the rank bitmask of a flush suit, and one indexed by a
+	// perfect hash of how many cards of each rank the hand holds. The tables
+	// are built on first use and take up about 110KB.
+	LookupEvaluator
+)
+
+var eval7 = eval7CardHand
+
+// Choose the strategy used to evaluate 7-card hands. (i.e. SubsetEvaluator
+// or LookupEvaluator) Both produce identical results. This is not safe to
+// call while other goroutines are evaluating hands, so it should be done
+// once at startup.
+func SetEvaluator(strategy int) {
+	switch strategy {
+	case LookupEvaluator:
+		eval7 = eval7CardHandLookup
+	default:
+		eval7 = eval7CardHand
+	}
+}
+
+// ----- LOOKUP EVALUATION FUNCTIONS -----------------------------------------
+
+// Number of ways to hold 7 cards when only their ranks matter: each of the
+// 13 ranks appears 0 to 4 times, for 7 cards in total.
+const rankHashSize = 49205
+
+var (
+	lookupOnce sync.Once
+	// best flush value for each rank bitmask of 5 to 7 cards of one suit
+	flush7 []uint16
+	// best non-flush value for each perfect hash of 7 rank counts
+	unsuited7 []uint16
+	// rankHashOffset[i][k][c] is added to the hash of a hand with c cards of
+	// rank i when k cards remain to be placed in ranks i and above
+	rankHashOffset [13][8][5]uint32
+)
+
+// Generate the equivalence value for a 7-card hand in a single pass by way
+// of the lookup tables.
+func eval7CardHandLookup(hand []Card) uint16 {
+	lookupOnce.Do(buildLookupTables)
+
+	// rank bitmasks indexed by the card's suit bit, shifted down
+	var suits [9]uint32
+	var counts [13]uint32
+	for _, card := range hand {
+		c := uint32(card)
+		suits[(c>>12)&0xF] |= c >> 16
+		counts[(c>>8)&0xF]++
+	}
+	// five or more cards of one suit rules out quads and full houses, so the
+	// best flush is the best hand
+	for s := 1; s <= 8; s <<= 1 {
+		if popcount(suits[s]) >= 5 {
+			return flush7[suits[s]]
+		}
+	}
+	return unsuited7[rankHash(&counts)]
+}
+
+// Compute the perfect hash of a hand's rank counts, which must add up to 7.
+// This is its position in the lexicographic ordering of all such counts.
+func rankHash(counts *[13]uint32) uint32 {
+	var hash uint32
+	k := uint32(7)
+	for i := 0; i < 13 && k > 0; i++ {
+		hash += rankHashOffset[i][k][counts[i]]
+		k -= counts[i]
+	}
+	return hash
+}
+
+// Build the lookup tables by running the subset evaluator over every flush
+// and every combination of rank counts.
+func buildLookupTables() {
+	// ways[n][k] is the number of ways to place k cards in n ranks
+	var ways [14][8]uint32
+	ways[0][0] = 1
+	for n := 1; n <= 13; n++ {
+		for k := 0; k <= 7; k++ {
+			for c := 0; c <= 4 && c <= k; c++ {
+				ways[n][k] += ways[n-1][k-c]
+			}
+		}
+	}
+	for i := 0; i < 13; i++ {
+		for k := 0; k <= 7; k++ {
+			for c := 1; c <= 4; c++ {
+				rankHashOffset[i][k][c] = rankHashOffset[i][k][c-1]
+				if k >= c-1 {
+					rankHashOffset[i][k][c] += ways[12-i][k-(c-1)]
+				}
+			}
+		}
+	}
+
+	flush7 = make([]uint16, 1<<13)
+	hand := make([]Card, 0, 7)
+	for ranks := 0; ranks < 1<<13; ranks++ {
+		if n := popcount(uint32(ranks)); n < 5 || n > 7 {
+			continue
+		}
+		hand = hand[:0]
+		for r := Deuce; r <= Ace; r++ {
+			if ranks&(1<<uint(r)) != 0 {
+				hand = append(hand, NewCard(r, Spade))
+			}
+		}
+		switch len(hand) {
+		case 5:
+			flush7[ranks] = eval5CardHand(hand)
+		case 6:
+			flush7[ranks] = eval6CardHand(hand)
+		default:
+			flush7[ranks] = eval7CardHand(hand)
+		}
+	}
+
+	// deal the cards of each rank count combination round-robin across the
+	// suits, so no suit gets more than two of them and none can make a flush
+	unsuited7 = make([]uint16, rankHashSize)
+	var counts [13]uint32
+	var fill func(rank int, left uint32)
+	fill = func(rank int, left uint32) {
+		if rank == 13 {
+			if left > 0 {
+				return
+			}
+			hand = hand[:0]
+			suit := 0
+			for r := Deuce; r <= Ace; r++ {
+				for c := uint32(0); c < counts[r]; c++ {
+					hand = append(hand, NewCard(r, Spade<<uint(suit%4)))
+					suit++
+				}
+			}
+			unsuited7[rankHash(&counts)] = eval7CardHand(hand)
+			return
+		}
+		for c := uint32(0); c <= 4 && c <= left; c++ {
+			counts[rank] = c
+			fill(rank+1, left-c)
+		}
+		counts[rank] = 0
+	}
+	fill(Deuce, 7)
+}
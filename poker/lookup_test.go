@@ -0,0 +1,90 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_lookup_evaluator_agrees_with_subset_evaluator(t *testing.T) {
+	deck := NewPokerDeck().cards
+	if testing.Short() {
+		// a large deterministic sample of hands
+		r := rand.New(rand.NewSource(1))
+		hand := make([]Card, 7)
+		for i := 0; i < 1000000; i++ {
+			for j, k := range r.Perm(CardsPerDeck)[:7] {
+				hand[j] = deck[k]
+			}
+			expectSameValue(t, hand)
+		}
+		return
+	}
+	// every one of the 133,784,560 7-card hands
+	hand := make([]Card, 7)
+	for a := 0; a < CardsPerDeck; a++ {
+		hand[0] = deck[a]
+		for b := a + 1; b < CardsPerDeck; b++ {
+			hand[1] = deck[b]
+			for c := b + 1; c < CardsPerDeck; c++ {
+				hand[2] = deck[c]
+				for d := c + 1; d < CardsPerDeck; d++ {
+					hand[3] = deck[d]
+					for e := d + 1; e < CardsPerDeck; e++ {
+						hand[4] = deck[e]
+						for f := e + 1; f < CardsPerDeck; f++ {
+							hand[5] = deck[f]
+							for g := f + 1; g < CardsPerDeck; g++ {
+								hand[6] = deck[g]
+								expectSameValue(t, hand)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_can_select_evaluator(t *testing.T) {
+	defer SetEvaluator(SubsetEvaluator)
+	hand := makeHand([]string{"Ts", "Tc", "8h", "Ks", "Td", "Kd", "Kh"})
+	for _, strategy := range []int{SubsetEvaluator, LookupEvaluator} {
+		SetEvaluator(strategy)
+		if rank := highRank(t, hand); rank != FullHouse {
+			t.Fatalf("expected %d but was %d", FullHouse, rank)
+		}
+	}
+}
+
+func Benchmark_subset_evaluator(b *testing.B) {
+	benchmarkEvaluator(b, eval7CardHand)
+}
+
+func Benchmark_lookup_evaluator(b *testing.B) {
+	benchmarkEvaluator(b, eval7CardHandLookup)
+}
+
+func benchmarkEvaluator(b *testing.B, eval func([]Card) uint16) {
+	deck := NewPokerDeck().cards
+	r := rand.New(rand.NewSource(1))
+	hands := make([][]Card, 4096)
+	for i := range hands {
+		hands[i] = make([]Card, 7)
+		for j, k := range r.Perm(CardsPerDeck)[:7] {
+			hands[i][j] = deck[k]
+		}
+	}
+	eval(hands[0])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		eval(hands[i%len(hands)])
+	}
+}
+
+func expectSameValue(t *testing.T, hand []Card) {
+	if slow, fast := eval7CardHand(hand), eval7CardHandLookup(hand); slow != fast {
+		t.Fatalf("expected %d but was %d for %s", slow, fast, PrintHand(hand))
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"strings"
+)
+
+// Low hand categories, from best to worst. Straights and flushes do not
+// count against a low hand, so only paired hands are worse than no pair.
+const (
+	lowNoPair = iota
+	lowOnePair
+	lowTwoPair
+	lowTrips
+	lowFullHouse
+	lowQuads
+)
+
+// ----- PUBLIC LOW HAND EVALUATION API --------------------------------------
+
+// Value of a hand for ace-to-five low (California lowball). Aces are low and
+// straights and flushes are ignored. The lower the value, the better the low,
+// so 5-4-3-2-A has the smallest value of any hand. Two hands with the same
+// value are tied.
+type LowValue uint32
+
+// Value for a hand that does not make a qualifying low.
+const NoLow LowValue = 0xFFFFFFFF
+
+// Does this low beat the other one?
+func (val LowValue) Beats(other LowValue) bool {
+	return val < other
+}
+
+// Is this low an unpaired eight or better?
+func (val LowValue) EightOrBetter() bool {
+	return val>>20 == lowNoPair && (val>>16)&0xF <= lowRank(Eight)
+}
+
+// Return the string representation of this low, highest card first. (e.g.
+// "7-5-4-3-2", "8-6-5-4-A", etc)
+func (val LowValue) String() string {
+	if val == NoLow {
+		return "no low"
+	}
+	var counts [13]int
+	for i := 0; i < 5; i++ {
+		counts[(val>>uint(4*i))&0xF]++
+	}
+	rv := make([]string, 0, 5)
+	for r := 12; r >= 0; r-- {
+		for i := 0; i < counts[r]; i++ {
+			rv = append(rv, rankStr[(r+12)%13])
+		}
+	}
+	return strings.Join(rv, "-")
+}
+
+// Determine the given hand's ace-to-five low value. If eightOrBetter is set,
+// hands that do not make an unpaired eight-low or better are reported as
+// NoLow. A Joker plays as the lowest rank the hand is missing. Returns
+// InvalidHandSize unless the hand contains 5, 6 or 7 cards.
+func EvaluateForLowA5(hand []Card, eightOrBetter bool) (LowValue, error) {
+	if len(hand) < 5 || len(hand) > 7 {
+		return NoLow, InvalidHandSize
+	}
+	val := evalLowA5(withLowJokers(hand))
+	if eightOrBetter && !val.EightOrBetter() {
+		return NoLow, nil
+	}
+	return val, nil
+}
+
+// Value of a hand for deuce-to-seven low (Kansas City lowball). Aces are high
+// and straights and flushes count against the hand, so the best possible hand
+// is 7-5-4-3-2 offsuit. The lower the value, the better the low. Two hands with
+// the same value are tied.
+type Low27Value uint64
+
+// Does this low beat the other one?
+func (val Low27Value) Beats(other Low27Value) bool {
+	return val < other
+}
+
+// Report the high ranking of this low. (i.e. HighCard, OnePair, etc)
+func (val Low27Value) Rank() int {
+	return handRank(uint16(7463 - ((val>>20)+1)/2))
+}
+
+// Return the string representation of this low, highest card first. (e.g.
+// "7-5-4-3-2", "K-Q-8-8-3", etc)
+func (val Low27Value) String() string {
+	rv := make([]string, 5)
+	for i := 0; i < 5; i++ {
+		rv[i] = rankStr[(val>>uint(4*(4-i)))&0xF]
+	}
+	return strings.Join(rv, "-")
+}
+
+// Determine the given hand's deuce-to-seven low value. Returns
+// InvalidHandSize unless the hand contains 5, 6 or 7 cards, and InvalidCard
+// if it holds the Joker.
+func EvaluateForLow27(hand []Card) (Low27Value, error) {
+	if len(hand) < 5 || len(hand) > 7 {
+		return 0, InvalidHandSize
+	}
+	if containsCard(hand, Joker) {
+		return 0, InvalidCard
+	}
+	switch len(hand) {
+	case 5:
+		return evalLow27Five(hand), nil
+	case 6:
+		return evalLow27Subhands(hand, perm6[:]), nil
+	}
+	return evalLow27Subhands(hand, perm7[:]), nil
+}
+
+// ----- LOW HAND EVALUATION FUNCTIONS ---------------------------------------
+
+// Rank bitmasks of A-5-4-3-2, which is only ace-high in deuce-to-seven, and
+// of A-6-4-3-2, the ace-high hand it sits just below.
+const (
+	wheelRanks  = 0x100F
+	aceSixRanks = 0x1017
+)
+
+// Report the rank of a card when aces play low. (i.e. Ace is 0, Deuce is 1,
+// and so on up to King at 12)
+func lowRank(rank int) LowValue {
+	return LowValue((rank + 1) % 13)
+}
+
+// Return the hand with each Joker replaced by a card of the lowest rank,
+// aces low, that the hand does not already hold. This is always the best
+// use of the joker for ace-to-five. Hands without a joker are returned as
+// they are.
+func withLowJokers(hand []Card) []Card {
+	var rv []Card
+	for i, card := range hand {
+		if card != Joker {
+			continue
+		}
+		if rv == nil {
+			rv = append([]Card(nil), hand...)
+		}
+		var bits uint32
+		for _, other := range rv {
+			if other != Joker {
+				bits |= 1 << uint(lowRank(other.Rank()))
+			}
+		}
+		low := 0
+		for bits&(1<<uint(low)) != 0 {
+			low++
+		}
+		rv[i] = NewCard((low+12)%13, Spade)
+	}
+	if rv == nil {
+		return hand
+	}
+	return rv
+}
+
+// Generate the ace-to-five low value for a 5, 6 or 7 card hand.
+func evalLowA5(hand []Card) LowValue {
+	// the rank bitmask of each card puts aces at the top; rotate them to the
+	// bottom so the five lowest distinct ranks are the five lowest set bits
+	var bits uint32
+	for _, card := range hand {
+		bits |= uint32(card) >> 16
+	}
+	bits = ((bits << 1) | (bits >> 12)) & 0x1FFF
+	if popcount(bits) >= 5 {
+		return lowFromBits(bits)
+	}
+	// at least one pair is unavoidable, so find the least damaging one
+	switch len(hand) {
+	case 5:
+		return evalLowA5Five(hand)
+	case 6:
+		return evalLowA5Subhands(hand, perm6[:])
+	}
+	return evalLowA5Subhands(hand, perm7[:])
+}
+
+// Generate the unpaired ace-to-five low value made by the five lowest ranks
+// set in the given bitmask, which has aces at the bottom.
+func lowFromBits(bits uint32) LowValue {
+	var val LowValue
+	for r, n := 0, 0; n < 5; r++ {
+		if bits&(1<<uint(r)) != 0 {
+			val |= LowValue(r) << uint(4*n)
+			n++
+		}
+	}
+	return val
+}
+
+// Evaluate the 5-card subsets of hand given by perms and return the best low
+// value found.
+func evalLowA5Subhands(hand []Card, perms [][5]uint32) LowValue {
+	best := NoLow
+	subhand := []Card{0, 0, 0, 0, 0}
+	for _, perm := range perms {
+		for j := 0; j < 5; j++ {
+			subhand[j] = hand[perm[j]]
+		}
+		if q := evalLowA5Five(subhand); q < best {
+			best = q
+		}
+	}
+	return best
+}
+
+// Generate the ace-to-five low value for exactly 5 cards. The category goes
+// in the top bits, followed by one nibble per card ordered by how much it
+// matters when comparing hands: larger groups first, then higher ranks.
+func evalLowA5Five(hand []Card) LowValue {
+	var counts [13]int
+	for _, card := range hand {
+		counts[lowRank(card.Rank())]++
+	}
+	var val LowValue
+	pairs, trips, quads := 0, 0, 0
+	for n := 4; n >= 1; n-- {
+		for r := 12; r >= 0; r-- {
+			if counts[r] != n {
+				continue
+			}
+			switch n {
+			case 4:
+				quads++
+			case 3:
+				trips++
+			case 2:
+				pairs++
+			}
+			for i := 0; i < n; i++ {
+				val = (val << 4) | LowValue(r)
+			}
+		}
+	}
+	var category LowValue
+	switch {
+	case quads > 0:
+		category = lowQuads
+	case trips > 0 && pairs > 0:
+		category = lowFullHouse
+	case trips > 0:
+		category = lowTrips
+	case pairs > 1:
+		category = lowTwoPair
+	case pairs > 0:
+		category = lowOnePair
+	default:
+		category = lowNoPair
+	}
+	return (category << 20) | val
+}
+
+// Evaluate the 5-card subsets of hand given by perms and return the best
+// deuce-to-seven low value found.
+func evalLow27Subhands(hand []Card, perms [][5]uint32) Low27Value {
+	var best Low27Value = 1<<64 - 1
+	subhand := []Card{0, 0, 0, 0, 0}
+	for _, perm := range perms {
+		for j := 0; j < 5; j++ {
+			subhand[j] = hand[perm[j]]
+		}
+		if q := evalLow27Five(subhand); q < best {
+			best = q
+		}
+	}
+	return best
+}
+
+// Generate the deuce-to-seven low value for exactly 5 cards. The high
+// equivalence value is inverted so that the worst high hand is the best low,
+// then doubled to leave room for A-5-4-3-2, which the high tables score as a
+// straight but which is really the lowest ace-high hand. The ranks of the
+// cards, highest first, go in the bottom bits for display.
+func evalLow27Five(hand []Card) Low27Value {
+	c1, c2, c3, c4, c5 := uint32(hand[0]), uint32(hand[1]), uint32(hand[2]), uint32(hand[3]), uint32(hand[4])
+	var ord Low27Value
+	if q := (c1 | c2 | c3 | c4 | c5) >> 16; q == wheelRanks {
+		var ace uint16
+		if (c1 & c2 & c3 & c4 & c5 & 0xF000) != 0 {
+			ace = flushes[aceSixRanks]
+		} else {
+			ace = unique5[aceSixRanks]
+		}
+		ord = 2*(7463-Low27Value(ace)) - 1
+	} else {
+		ord = 2 * (7463 - Low27Value(eval5CardHandFast(c1, c2, c3, c4, c5)))
+	}
+	var ranks [5]int
+	for i, card := range hand {
+		ranks[i] = card.Rank()
+	}
+	for i := 1; i < 5; i++ {
+		for j := i; j > 0 && ranks[j] > ranks[j-1]; j-- {
+			ranks[j], ranks[j-1] = ranks[j-1], ranks[j]
+		}
+	}
+	val := ord
+	for _, r := range ranks {
+		val = (val << 4) | Low27Value(r)
+	}
+	return val
+}
+
+// Count the number of bits set in the given word.
+func popcount(bits uint32) int {
+	n := 0
+	for ; bits != 0; bits &= bits - 1 {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_can_detect_wheel_for_low(t *testing.T) {
+	// the wheel is the nuts in ace-to-five, even when suited
+	hand := makeHand([]string{"5c", "Kd", "3c", "Ac", "4c", "2c", "Kh"})
+	low := lowA5(t, hand, false)
+	if low.String() != "5-4-3-2-A" {
+		t.Fatalf("expected 5-4-3-2-A but was %s", low)
+	}
+	other := lowA5(t, makeHand([]string{"6c", "4d", "3c", "Ac", "2h"}), false)
+	if !low.Beats(other) {
+		t.Fatalf("expected %s to beat %s", low, other)
+	}
+}
+
+func Test_can_compare_lows_by_highest_card(t *testing.T) {
+	a := lowA5(t, makeHand([]string{"7c", "5d", "4c", "3h", "2s", "Kd", "Qs"}), false)
+	b := lowA5(t, makeHand([]string{"7h", "6d", "4c", "3h", "2s", "Kd", "Qs"}), false)
+	if a.String() != "7-5-4-3-2" {
+		t.Fatalf("expected 7-5-4-3-2 but was %s", a)
+	}
+	if !a.Beats(b) {
+		t.Fatalf("expected %s to beat %s", a, b)
+	}
+}
+
+func Test_can_detect_paired_low(t *testing.T) {
+	// razz hand with only four distinct ranks must play a pair
+	hand := makeHand([]string{"Ac", "Ad", "2c", "2h", "3s", "4d", "4h"})
+	low := lowA5(t, hand, false)
+	if low.String() != "4-3-2-A-A" {
+		t.Fatalf("expected 4-3-2-A-A but was %s", low)
+	}
+	// any unpaired hand beats a paired one
+	king := lowA5(t, makeHand([]string{"Kc", "Qd", "Jc", "Th", "9s"}), false)
+	if !king.Beats(low) {
+		t.Fatalf("expected %s to beat %s", king, low)
+	}
+}
+
+func Test_eight_or_better_qualifier(t *testing.T) {
+	eight := makeHand([]string{"8c", "6d", "4c", "3h", "2s", "Kd", "Qs"})
+	if low := lowA5(t, eight, true); low == NoLow {
+		t.Fatalf("expected 8-6-4-3-2 to qualify")
+	}
+	nine := makeHand([]string{"9c", "6d", "4c", "3h", "2s", "Kd", "6s"})
+	if low := lowA5(t, nine, true); low != NoLow {
+		t.Fatalf("expected %s not to qualify", low)
+	}
+	if low := lowA5(t, nine, false); low.String() != "9-6-4-3-2" {
+		t.Fatalf("expected 9-6-4-3-2 but was %s", low)
+	}
+}
+
+func Test_can_detect_number_one_for_deuce_to_seven(t *testing.T) {
+	// 7-5-4-3-2 offsuit is the nuts in deuce-to-seven
+	best := low27(t, makeHand([]string{"7c", "5d", "4c", "3h", "2s"}))
+	if best.String() != "7-5-4-3-2" {
+		t.Fatalf("expected 7-5-4-3-2 but was %s", best)
+	}
+	for _, cards := range [][]string{
+		{"7c", "6d", "4c", "3h", "2s"},
+		{"8c", "5d", "4c", "3h", "2s"},
+		{"7c", "5c", "4c", "3c", "2c"},
+		{"5c", "4d", "3c", "2h", "As"},
+	} {
+		other := low27(t, makeHand(cards))
+		if !best.Beats(other) {
+			t.Fatalf("expected %s to beat %s", best, other)
+		}
+	}
+}
+
+func Test_straight_counts_against_deuce_to_seven(t *testing.T) {
+	// 8-7-6-5-4 is a straight, so any unpaired eight-low beats it
+	straight := low27(t, makeHand([]string{"8c", "7d", "6c", "5h", "4s"}))
+	if straight.Rank() != Straight {
+		t.Fatalf("expected %d but was %d", Straight, straight.Rank())
+	}
+	eight := low27(t, makeHand([]string{"8c", "7d", "6c", "5h", "3s"}))
+	if !eight.Beats(straight) {
+		t.Fatalf("expected %s to beat %s", eight, straight)
+	}
+}
+
+func Test_flush_counts_against_deuce_to_seven(t *testing.T) {
+	flush := low27(t, makeHand([]string{"7h", "5h", "4h", "3h", "2h"}))
+	if flush.Rank() != Flush {
+		t.Fatalf("expected %d but was %d", Flush, flush.Rank())
+	}
+	pair := low27(t, makeHand([]string{"Kc", "Kd", "Qc", "Jh", "9s"}))
+	if !pair.Beats(flush) {
+		t.Fatalf("expected %s to beat %s", pair, flush)
+	}
+}
+
+func Test_aces_are_high_for_deuce_to_seven(t *testing.T) {
+	// A-5-4-3-2 is not a straight; it is the worst ace-high hand there is
+	wheel := low27(t, makeHand([]string{"As", "5d", "4c", "3h", "2s"}))
+	if wheel.Rank() != HighCard {
+		t.Fatalf("expected %d but was %d", HighCard, wheel.Rank())
+	}
+	king := low27(t, makeHand([]string{"Ks", "Qd", "Jc", "Th", "8s"}))
+	if !king.Beats(wheel) {
+		t.Fatalf("expected %s to beat %s", king, wheel)
+	}
+	ace := low27(t, makeHand([]string{"As", "6d", "4c", "3h", "2s"}))
+	if !wheel.Beats(ace) {
+		t.Fatalf("expected %s to beat %s", wheel, ace)
+	}
+	// same holds when the wheel is suited, as a flush
+	flush := low27(t, makeHand([]string{"As", "5s", "4s", "3s", "2s"}))
+	if flush.Rank() != Flush {
+		t.Fatalf("expected %d but was %d", Flush, flush.Rank())
+	}
+	aceFlush := low27(t, makeHand([]string{"As", "6s", "4s", "3s", "2s"}))
+	if !flush.Beats(aceFlush) {
+		t.Fatalf("expected %s to beat %s", flush, aceFlush)
+	}
+}
+
+func Test_pairs_count_against_deuce_to_seven(t *testing.T) {
+	pair := low27(t, makeHand([]string{"2c", "2d", "3c", "4h", "5s"}))
+	if pair.Rank() != OnePair {
+		t.Fatalf("expected %d but was %d", OnePair, pair.Rank())
+	}
+	ace := low27(t, makeHand([]string{"Ac", "Kd", "Qc", "Jh", "9s"}))
+	if !ace.Beats(pair) {
+		t.Fatalf("expected %s to beat %s", ace, pair)
+	}
+}
+
+func Test_can_find_best_deuce_to_seven_in_seven_cards(t *testing.T) {
+	hand := makeHand([]string{"7c", "6d", "5c", "4h", "3s", "2d", "Kh"})
+	low := low27(t, hand)
+	if low.String() != "7-5-4-3-2" {
+		t.Fatalf("expected 7-5-4-3-2 but was %s", low)
+	}
+}
+
+func Test_deuce_to_seven_ties(t *testing.T) {
+	a := low27(t, makeHand([]string{"7c", "5d", "4c", "3h", "2s"}))
+	b := low27(t, makeHand([]string{"7d", "5h", "4s", "3s", "2c"}))
+	if a.Beats(b) || b.Beats(a) {
+		t.Fatalf("expected %s to tie %s", a, b)
+	}
+}
+
+func Test_joker_plays_lowest_missing_rank_for_low(t *testing.T) {
+	hand := makeHand([]string{"Jk", "5d", "3c", "Ac", "Kh"})
+	low := lowA5(t, hand, false)
+	if low.String() != "K-5-3-2-A" {
+		t.Fatalf("expected K-5-3-2-A but was %s", low)
+	}
+	wheel := lowA5(t, makeHand([]string{"Jk", "5d", "4c", "3c", "2h"}), true)
+	if wheel.String() != "5-4-3-2-A" {
+		t.Fatalf("expected 5-4-3-2-A but was %s", wheel)
+	}
+}
+
+func Test_deuce_to_seven_rejects_joker(t *testing.T) {
+	hand := makeHand([]string{"Jk", "7d", "5c", "4c", "3h"})
+	if _, err := EvaluateForLow27(hand); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+}
+
+func lowA5(t *testing.T, hand []Card, eightOrBetter bool) LowValue {
+	low, err := EvaluateForLowA5(hand, eightOrBetter)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	return low
+}
+
+func low27(t *testing.T, hand []Card) Low27Value {
+	low, err := EvaluateForLow27(hand)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	return low
+}
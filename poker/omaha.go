@@ -0,0 +1,102 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+)
+
+var InvalidOmahaHand = fmt.Errorf("omaha hand must have 4 or 5 hole cards and 3 to 5 board cards")
+
+// ----- PUBLIC OMAHA EVALUATION API -----------------------------------------
+
+// Determine the best high value for an Omaha hand and, if low is set, the
+// best eight-or-better ace-to-five low. Every hand must use exactly two of
+// its hole cards and three cards from the board. The low is NoLow if it was
+// not asked for or no combination of cards qualifies. Returns
+// InvalidOmahaHand unless there are 4 or 5 hole cards and 3 to 5 board cards,
+// and InvalidCard if either holds the Joker.
+func EvaluateOmaha(hole, board []Card, low bool) (HandValue, LowValue, error) {
+	if len(hole) < 4 || len(hole) > 5 || len(board) < 3 || len(board) > 5 {
+		return 0, NoLow, InvalidOmahaHand
+	}
+	if containsCard(hole, Joker) || containsCard(board, Joker) {
+		return 0, NoLow, InvalidCard
+	}
+	high, lo := evalOmaha(hole, board, low)
+	return HandValue(high), lo, nil
+}
+
+// ----- OMAHA EVALUATION FUNCTIONS ------------------------------------------
+
+// Two or three cards from one side of an Omaha hand, along with the low
+// rank bits they contribute if they can all be used for an eight-or-better
+// low. (i.e. they are unpaired and no higher than an eight)
+type omahaPart struct {
+	c1, c2, c3 uint32
+	lowBits    uint32
+}
+
+// Generate the best high and low values for an Omaha hand by pairing every
+// 2 hole cards with every 3 board cards. There are at most 10 of each, so
+// the combinations are collected up front into fixed arrays to keep this
+// free of allocations.
+func evalOmaha(hole, board []Card, low bool) (uint16, LowValue) {
+	var pairs, triples [10]omahaPart
+	np, nt := 0, 0
+	for i := 0; i < len(hole); i++ {
+		for j := i + 1; j < len(hole); j++ {
+			pairs[np] = omahaPart{c1: uint32(hole[i]), c2: uint32(hole[j])}
+			pairs[np].lowBits = omahaLowBits(hole[i], hole[j])
+			np++
+		}
+	}
+	for i := 0; i < len(board); i++ {
+		for j := i + 1; j < len(board); j++ {
+			for k := j + 1; k < len(board); k++ {
+				triples[nt] = omahaPart{c1: uint32(board[i]), c2: uint32(board[j]), c3: uint32(board[k])}
+				triples[nt].lowBits = omahaLowBits(board[i], board[j], board[k])
+				nt++
+			}
+		}
+	}
+
+	var high uint16 = 0xFFFF
+	var lowBits uint32 = 0xFFFFFFFF
+	for _, p := range pairs[:np] {
+		for _, t := range triples[:nt] {
+			if q := eval5CardHandFast(p.c1, p.c2, t.c1, t.c2, t.c3); q < high {
+				high = q
+			}
+			if !low || p.lowBits == 0 || t.lowBits == 0 {
+				continue
+			}
+			// with five distinct ranks, comparing the bitmasks compares the
+			// lows from the highest card down
+			if bits := p.lowBits | t.lowBits; popcount(bits) == 5 && bits < lowBits {
+				lowBits = bits
+			}
+		}
+	}
+	if lowBits == 0xFFFFFFFF {
+		return high, NoLow
+	}
+	return high, lowFromBits(lowBits)
+}
+
+// Report the ace-low rank bits of the given cards, or zero if any of them
+// is paired or higher than an eight.
+func omahaLowBits(cards ...Card) uint32 {
+	var bits uint32
+	for _, card := range cards {
+		r := lowRank(card.Rank())
+		if r > lowRank(Eight) {
+			return 0
+		}
+		bits |= 1 << uint(r)
+	}
+	if popcount(bits) != len(cards) {
+		return 0
+	}
+	return bits
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_omaha_must_use_two_hole_cards(t *testing.T) {
+	// four spades on board but only one in hand is no flush in Omaha
+	hole := makeHand([]string{"As", "Kd", "Qd", "2c"})
+	board := makeHand([]string{"3s", "7s", "9s", "Js", "4h"})
+	high, _ := omaha(t, hole, board, false)
+	if high.Rank() != HighCard {
+		t.Fatalf("expected %d but was %d", HighCard, high.Rank())
+	}
+	// quads in the hole only play as a pair
+	hole = makeHand([]string{"Ks", "Kd", "Kh", "Kc"})
+	board = makeHand([]string{"3s", "7d", "9c"})
+	high, _ = omaha(t, hole, board, false)
+	if high.Rank() != OnePair {
+		t.Fatalf("expected %d but was %d", OnePair, high.Rank())
+	}
+}
+
+func Test_omaha_must_use_three_board_cards(t *testing.T) {
+	// a straight on board does not play without two hole cards to match
+	hole := makeHand([]string{"Ac", "Ad", "Kd", "Kc"})
+	board := makeHand([]string{"5s", "6h", "7d", "8c", "9s"})
+	high, _ := omaha(t, hole, board, false)
+	if high.Rank() != OnePair {
+		t.Fatalf("expected %d but was %d", OnePair, high.Rank())
+	}
+}
+
+func Test_omaha_finds_best_low(t *testing.T) {
+	hole := makeHand([]string{"Ac", "2d", "Kd", "Kc", "3h"})
+	board := makeHand([]string{"5s", "6h", "8d", "Jc", "Qs"})
+	// only two of the three low hole cards may play
+	_, low := omaha(t, hole, board, true)
+	if low.String() != "8-6-5-2-A" {
+		t.Fatalf("expected 8-6-5-2-A but was %s", low)
+	}
+	// counterfeited: the only low cards in hand pair the board
+	hole = makeHand([]string{"5c", "6d", "Kd", "Kc"})
+	_, low = omaha(t, hole, board, true)
+	if low != NoLow {
+		t.Fatalf("expected no low but was %s", low)
+	}
+	// two low cards on board is never enough
+	board = makeHand([]string{"2s", "3h", "Td", "Jc", "Qs"})
+	hole = makeHand([]string{"Ac", "4d", "5d", "6c"})
+	_, low = omaha(t, hole, board, true)
+	if low != NoLow {
+		t.Fatalf("expected no low but was %s", low)
+	}
+}
+
+func Test_rejects_omaha_hands_of_wrong_size(t *testing.T) {
+	hole := makeHand([]string{"Ac", "2d"})
+	board := makeHand([]string{"5s", "6h", "8d", "Jc", "Qs"})
+	if _, _, err := EvaluateOmaha(hole, board, true); err != InvalidOmahaHand {
+		t.Fatalf("expected InvalidOmahaHand but was %v", err)
+	}
+}
+
+func Test_omaha_rejects_joker(t *testing.T) {
+	hole := makeHand([]string{"Ac", "2d", "Kd", "Jk"})
+	board := makeHand([]string{"5s", "6h", "8d", "Jc", "Qs"})
+	if _, _, err := EvaluateOmaha(hole, board, true); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+	hole = makeHand([]string{"Ac", "2d", "Kd", "Kc"})
+	board = makeHand([]string{"5s", "6h", "Jk"})
+	if _, _, err := EvaluateOmaha(hole, board, true); err != InvalidCard {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+}
+
+func Benchmark_omaha5_river(b *testing.B) {
+	hole := makeHand([]string{"Ac", "2d", "Kd", "Kc", "3h"})
+	board := makeHand([]string{"5s", "6h", "8d", "Jc", "Qs"})
+	for i := 0; i < b.N; i++ {
+		EvaluateOmaha(hole, board, true)
+	}
+}
+
+func omaha(t *testing.T, hole, board []Card, low bool) (HandValue, LowValue) {
+	high, lo, err := EvaluateOmaha(hole, board, low)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s on %s: %v", PrintHand(hole), PrintHand(board), err)
+	}
+	return high, lo
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+)
+
+var InvalidOutsBoard = fmt.Errorf("outs can only be counted on the flop or turn")
+
+// ----- PUBLIC OUTS API -----------------------------------------------------
+
+// Report of how a Hold'em hand can improve. Current is the hand's ranking
+// now. (i.e. OnePair) Outs holds the cards that would improve that ranking
+// if dealt next, keyed by the ranking they make. Tainted holds the cards that
+// would improve it only by giving the board itself that ranking, such as a
+// card pairing the board, which every opponent shares; these are not counted
+// as outs. Rivers counts how many of the possible runouts to the river leave
+// the hand at each ranking, however it is made, out of Runouts in total.
+type OutsReport struct {
+	Current int
+	Outs    map[int][]Card
+	Tainted map[int][]Card
+	Rivers  map[int]int
+	Runouts int
+}
+
+// Report the total number of outs, not counting tainted cards.
+func (report OutsReport) Total() int {
+	n := 0
+	for _, cards := range report.Outs {
+		n += len(cards)
+	}
+	return n
+}
+
+// Report the chance of ending up with the given ranking on the river.
+func (report OutsReport) Probability(rank int) float64 {
+	if report.Runouts == 0 {
+		return 0
+	}
+	return float64(report.Rivers[rank]) / float64(report.Runouts)
+}
+
+// Count the outs for the given hole cards on a flop or turn, leaving out any
+// dead cards, and work out how likely each final ranking is by the river.
+func CountOuts(hole, board, dead []Card) (OutsReport, error) {
+	if len(hole) != 2 {
+		return OutsReport{}, InvalidHoleCards
+	}
+	if len(board) < 3 || len(board) > 4 {
+		return OutsReport{}, InvalidOutsBoard
+	}
+	stub, err := remainingCards(hole, board, dead)
+	if err != nil {
+		return OutsReport{}, err
+	}
+	hand := make([]Card, 0, 2+BoardSize)
+	hand = append(append(hand, hole...), board...)
+	current, err := EvaluateForHigh(hand)
+	if err != nil {
+		return OutsReport{}, err
+	}
+	report := OutsReport{
+		Current: current,
+		Outs:    make(map[int][]Card),
+		Tainted: make(map[int][]Card),
+		Rivers:  make(map[int]int),
+	}
+	shared := make([]Card, 0, BoardSize)
+	shared = append(shared, board...)
+	shared = shared[:len(shared)+1]
+
+	// rankings run from StraightFlush (1) to HighCard (9), so a lower
+	// ranking is an improvement
+	next := hand[:len(hand)+1]
+	for i, card := range stub {
+		next[len(next)-1] = card
+		rank, err := EvaluateForHigh(next)
+		if err != nil {
+			return OutsReport{}, err
+		}
+		if rank < current {
+			shared[len(shared)-1] = card
+			if boardRank(shared) <= rank {
+				report.Tainted[rank] = append(report.Tainted[rank], card)
+			} else {
+				report.Outs[rank] = append(report.Outs[rank], card)
+			}
+		}
+		if len(next) == 2+BoardSize {
+			report.Rivers[rank]++
+			report.Runouts++
+			continue
+		}
+		river := next[:len(next)+1]
+		for _, last := range stub[i+1:] {
+			river[len(river)-1] = last
+			rank, err := EvaluateForHigh(river)
+			if err != nil {
+				return OutsReport{}, err
+			}
+			report.Rivers[rank]++
+			report.Runouts++
+		}
+	}
+	return report, nil
+}
+
+// ----- OUTS FUNCTIONS ------------------------------------------------------
+
+// Report the ranking the board cards make on their own. Boards of fewer than
+// five cards can only make pairs, two pair, trips or quads.
+func boardRank(board []Card) int {
+	if len(board) >= 5 {
+		rank, _ := EvaluateForHigh(board)
+		return rank
+	}
+	var counts [13]int
+	pairs := 0
+	rank := HighCard
+	for _, card := range board {
+		counts[card.Rank()]++
+		switch counts[card.Rank()] {
+		case 2:
+			pairs++
+		case 3:
+			rank = ThreeOfAKind
+		case 4:
+			rank = FourOfAKind
+		}
+	}
+	switch {
+	case rank != HighCard:
+		return rank
+	case pairs == 2:
+		return TwoPair
+	case pairs == 1:
+		return OnePair
+	}
+	return HighCard
+}
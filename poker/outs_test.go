@@ -0,0 +1,78 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_can_count_flush_draw_outs(t *testing.T) {
+	hole := makeHand([]string{"Ah", "Kh"})
+	board := makeHand([]string{"2h", "7h", "Qc"})
+	report := outs(t, hole, board, nil)
+	if report.Current != HighCard {
+		t.Fatalf("expected %d but was %d", HighCard, report.Current)
+	}
+	// nine hearts for the flush and three each of the aces and kings for a
+	// pair; the deuces, sevens and queens only pair the board
+	if n := len(report.Outs[Flush]); n != 9 {
+		t.Fatalf("expected 9 flush outs but was %d", n)
+	}
+	if n := len(report.Outs[OnePair]); n != 6 || report.Total() != 15 {
+		t.Fatalf("expected 6 pair outs but was %d", n)
+	}
+	if n := len(report.Tainted[OnePair]); n != 8 {
+		t.Fatalf("expected 8 tainted pair cards but was %d", n)
+	}
+	if report.Runouts != 1081 {
+		t.Fatalf("expected 1081 runouts but was %d", report.Runouts)
+	}
+	total := 0
+	for _, n := range report.Rivers {
+		total += n
+	}
+	if total != report.Runouts {
+		t.Fatalf("expected %d runouts to be counted but was %d", report.Runouts, total)
+	}
+}
+
+func Test_board_flush_cards_are_tainted(t *testing.T) {
+	hole := makeHand([]string{"Ac", "2c"})
+	board := makeHand([]string{"Kh", "9h", "5h", "3h"})
+	report := outs(t, hole, board, nil)
+	if n := len(report.Outs[Flush]); n != 0 {
+		t.Fatalf("expected no flush outs but was %d", n)
+	}
+	if n := len(report.Tainted[Flush]); n != 9 {
+		t.Fatalf("expected 9 tainted flush cards but was %d", n)
+	}
+}
+
+func Test_dead_cards_are_not_outs(t *testing.T) {
+	hole := makeHand([]string{"Ah", "Kh"})
+	board := makeHand([]string{"2h", "7h", "Qc", "3s"})
+	dead := makeHand([]string{"9h", "Th"})
+	report := outs(t, hole, board, dead)
+	if n := len(report.Outs[Flush]); n != 7 {
+		t.Fatalf("expected 7 flush outs but was %d", n)
+	}
+	if report.Runouts != 44 {
+		t.Fatalf("expected 44 runouts but was %d", report.Runouts)
+	}
+	expectClose(t, report.Probability(Flush), 7.0/44)
+}
+
+func Test_rejects_outs_before_flop(t *testing.T) {
+	hole := makeHand([]string{"Ah", "Kh"})
+	if _, err := CountOuts(hole, nil, nil); err != InvalidOutsBoard {
+		t.Fatalf("expected InvalidOutsBoard but was %v", err)
+	}
+}
+
+func outs(t *testing.T, hole, board, dead []Card) OutsReport {
+	report, err := CountOuts(hole, board, dead)
+	if err != nil {
+		t.Fatalf("cannot count outs: %v", err)
+	}
+	return report
+}
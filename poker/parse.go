@@ -0,0 +1,133 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var InvalidCard = fmt.Errorf("invalid card")
+
+// ----- PUBLIC CARD PARSING API ---------------------------------------------
+
+// Parse a single card from its rank and suit. (e.g. "Td", "10d", "T♦", etc)
+// Ranks are 2-9, 10 or T, J, Q, K and A in either case; suits are c, d, h
+// and s in either case, or their unicode symbols. The Joker is "Jk" in any
+// case, or one of the unicode joker cards. Returns an error wrapping
+// InvalidCard if the string is not exactly one card.
+func ParseCard(s string) (Card, error) {
+	s = strings.TrimSpace(s)
+	card, n, err := parseCardPrefix(s)
+	if err != nil {
+		return 0, err
+	}
+	if n != len(s) {
+		return 0, fmt.Errorf("%w %q: unexpected %q after card", InvalidCard, s, s[n:])
+	}
+	return card, nil
+}
+
+// Parse a list of cards separated by commas and/or spaces, such as
+// "Ah Kh", "Td,9d" or the output of PrintHand. Cards may also be run
+// together, as in "AhKh". Returns an error wrapping InvalidCard for anything
+// that is not a card, or DuplicateCard if a card appears more than once.
+func ParseHand(s string) ([]Card, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = s[1 : len(s)-1]
+	}
+	hand := []Card{}
+	seen := make(map[Card]bool)
+	toks := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for _, tok := range toks {
+		for rest := tok; rest != ""; {
+			card, n, err := parseCardPrefix(rest)
+			if err != nil {
+				return nil, err
+			}
+			if seen[card] {
+				return nil, fmt.Errorf("%w: %s", DuplicateCard, card)
+			}
+			seen[card] = true
+			hand = append(hand, card)
+			rest = rest[n:]
+		}
+	}
+	return hand, nil
+}
+
+// ----- CARD PARSING FUNCTIONS ----------------------------------------------
+
+// Parse the card at the start of s, returning it and the number of bytes of
+// s that it took up.
+func parseCardPrefix(s string) (Card, int, error) {
+	if s == "" {
+		return 0, 0, fmt.Errorf("%w: missing card", InvalidCard)
+	}
+	if len(s) >= 2 && strings.EqualFold(s[:2], "jk") {
+		return Joker, 2, nil
+	}
+	if r, size := utf8.DecodeRuneInString(s); r == blackJoker || r == whiteJoker {
+		return Joker, size, nil
+	}
+	rank, n := Ten, 2
+	if !strings.HasPrefix(s, "10") {
+		var ok bool
+		if rank, ok = parseRank(s[0]); !ok {
+			return 0, 0, fmt.Errorf("%w %q: unknown rank", InvalidCard, s)
+		}
+		n = 1
+	}
+	r, size := utf8.DecodeRuneInString(s[n:])
+	if size == 0 {
+		return 0, 0, fmt.Errorf("%w %q: missing suit", InvalidCard, s)
+	}
+	suit, ok := parseSuit(r)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w %q: unknown suit", InvalidCard, s)
+	}
+	return NewCard(rank, suit), n + size, nil
+}
+
+// Parse a rank character. (e.g. '2', 'T', 'a', etc)
+func parseRank(c byte) (int, bool) {
+	for r, s := range rankStr {
+		if s[0] == c || (c >= 'a' && c <= 'z' && s[0] == c-'a'+'A') {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// Parse a suit character. (e.g. 'c', 'D', '♥', '♤', etc)
+func parseSuit(c rune) (int, bool) {
+	switch c {
+	case 'c', 'C', '♣', '♧':
+		return Club, true
+	case 'd', 'D', '♦', '♢':
+		return Diamond, true
+	case 'h', 'H', '♥', '♡':
+		return Heart, true
+	case 's', 'S', '♠', '♤':
+		return Spade, true
+	}
+	return 0, false
+}
+
+// Parse a card from its rank and suit characters.
+func parseRankSuit(rc, sc byte) (Card, bool) {
+	rank, ok := parseRank(rc)
+	if !ok {
+		return 0, false
+	}
+	suit, ok := parseSuit(rune(sc))
+	if !ok {
+		return 0, false
+	}
+	return NewCard(rank, suit), true
+}
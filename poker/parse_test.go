@@ -0,0 +1,76 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_can_parse_cards(t *testing.T) {
+	for s, expected := range map[string]Card{
+		"Td":  NewCard(Ten, Diamond),
+		"10d": NewCard(Ten, Diamond),
+		"td":  NewCard(Ten, Diamond),
+		"T♦":  NewCard(Ten, Diamond),
+		"A♠":  NewCard(Ace, Spade),
+		"2C":  NewCard(Deuce, Club),
+		" 9h": NewCard(Nine, Heart),
+		"Jk":  Joker,
+		"JK":  Joker,
+		"🃏":   Joker,
+	} {
+		card, err := ParseCard(s)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %v", s, err)
+		}
+		if card != expected {
+			t.Fatalf("expected %s but was %s for %q", expected, card, s)
+		}
+	}
+}
+
+func Test_rejects_invalid_cards(t *testing.T) {
+	for _, s := range []string{"", "T", "1d", "Xd", "Tx", "Tdd", "11d"} {
+		if _, err := ParseCard(s); !errors.Is(err, InvalidCard) {
+			t.Fatalf("expected InvalidCard for %q but was %v", s, err)
+		}
+	}
+}
+
+func Test_can_parse_hands(t *testing.T) {
+	for _, s := range []string{"Ah Kh Qh", "Ah,Kh,Qh", "Ah, Kh  Qh", "AhKhQh", "(Ah,Kh,Qh)", "A♥ K♥ Q♥"} {
+		hand, err := ParseHand(s)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %v", s, err)
+		}
+		if PrintHand(hand) != "(Ah,Kh,Qh)" {
+			t.Fatalf("expected (Ah,Kh,Qh) but was %s for %q", PrintHand(hand), s)
+		}
+	}
+}
+
+func Test_hands_round_trip_through_print(t *testing.T) {
+	deck := NewPokerDeck()
+	hand, err := ParseHand(PrintHand(deck.cards))
+	if err != nil {
+		t.Fatalf("cannot parse full deck: %v", err)
+	}
+	if PrintHand(hand) != PrintHand(deck.cards) {
+		t.Fatalf("expected %s but was %s", PrintHand(deck.cards), PrintHand(hand))
+	}
+	for _, card := range deck.cards {
+		if parsed, err := ParseCard(card.String()); err != nil || parsed != card {
+			t.Fatalf("expected %s but was %s (%v)", card, parsed, err)
+		}
+	}
+}
+
+func Test_rejects_invalid_hands(t *testing.T) {
+	if _, err := ParseHand("Ah Kh Ah"); !errors.Is(err, DuplicateCard) {
+		t.Fatalf("expected DuplicateCard but was %v", err)
+	}
+	if _, err := ParseHand("Ah Kx"); !errors.Is(err, InvalidCard) {
+		t.Fatalf("expected InvalidCard but was %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"sort"
+)
+
+// ----- PUBLIC POT API ------------------------------------------------------
+
+// A main or side pot: the chips in it and the seats that can win it.
+type Pot struct {
+	Amount   uint32
+	Eligible []int
+}
+
+// Keeps track of what each seat has put into the pot over a hand, so the main
+// pot and any side pots can be built when players are all in for different
+// amounts.
+type PotManager struct {
+	contributions []uint32
+	folded        []bool
+}
+
+// Create a pot manager for a table with the given number of seats.
+func NewPotManager(seats int) *PotManager {
+	return &PotManager{
+		contributions: make([]uint32, seats),
+		folded:        make([]bool, seats),
+	}
+}
+
+// Add chips put in by the given seat.
+func (pm *PotManager) Contribute(seat int, amount uint32) {
+	pm.contributions[seat] += amount
+}
+
+// Mark the given seat as folded. Its chips stay in the pot, but it can no
+// longer win any of it.
+func (pm *PotManager) Fold(seat int) {
+	pm.folded[seat] = true
+}
+
+// Report the chips the given seat has put in over the hand.
+func (pm *PotManager) Contribution(seat int) uint32 {
+	return pm.contributions[seat]
+}
+
+// Report the chips in all of the pots together.
+func (pm *PotManager) Total() uint32 {
+	var total uint32
+	for _, amount := range pm.contributions {
+		total += amount
+	}
+	return total
+}
+
+// Empty the pots for a new hand.
+func (pm *PotManager) Reset() {
+	for i := range pm.contributions {
+		pm.contributions[i] = 0
+		pm.folded[i] = false
+	}
+}
+
+// Build the main pot and side pots, main pot first. Each distinct amount put
+// in by a player still in the hand tops off a pot, which every seat puts its
+// chips into up to that amount and which only the players who put in at
+// least that much can win. Chips put in by folded players beyond the largest
+// such amount go in the last pot. A last pot with a single eligible seat
+// holds a bet nobody called, which goes back to its owner when the pots are
+// awarded.
+func (pm *PotManager) Pots() []Pot {
+	var levels []uint32
+	for i, amount := range pm.contributions {
+		if !pm.folded[i] && amount > 0 {
+			levels = append(levels, amount)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	var pots []Pot
+	var prev uint32
+	for _, top := range levels {
+		if top == prev {
+			continue
+		}
+		pot := Pot{}
+		for i, amount := range pm.contributions {
+			pot.Amount += capped(amount, top) - capped(amount, prev)
+			if !pm.folded[i] && amount >= top {
+				pot.Eligible = append(pot.Eligible, i)
+			}
+		}
+		pots = append(pots, pot)
+		prev = top
+	}
+	var excess uint32
+	for _, amount := range pm.contributions {
+		if amount > prev {
+			excess += amount - prev
+		}
+	}
+	if excess > 0 {
+		if len(pots) == 0 {
+			// nobody still in put anything in, such as a big blind walk
+			// posted as a dead blind, so the pot goes to whoever is left
+			pot := Pot{Amount: excess}
+			for i := range pm.contributions {
+				if !pm.folded[i] {
+					pot.Eligible = append(pot.Eligible, i)
+				}
+			}
+			return []Pot{pot}
+		}
+		pots[len(pots)-1].Amount += excess
+	}
+	return pots
+}
+
+// Award each pot to the best hands among the seats eligible for it,
+// returning the amount each seat wins. Contenders are indexed by seat, and
+// first is the seat to the left of the button, from which odd chips are
+// handed out. Each pot is split with SplitPot on its own, so a player can
+// win a side pot while losing the main pot and vice versa.
+func (pm *PotManager) Award(contenders []Contender, hiLo bool, first int) []uint32 {
+	cnt := len(pm.contributions)
+	won := make([]uint32, cnt)
+	for _, pot := range pm.Pots() {
+		// put the eligible seats in order from the left of the button
+		seats := make([]int, len(pot.Eligible))
+		copy(seats, pot.Eligible)
+		sort.Slice(seats, func(i, j int) bool {
+			return (seats[i]-first+cnt)%cnt < (seats[j]-first+cnt)%cnt
+		})
+		eligible := make([]Contender, len(seats))
+		for i, seat := range seats {
+			eligible[i] = contenders[seat]
+		}
+		for i, amount := range SplitPot(pot.Amount, eligible, hiLo) {
+			won[seats[i]] += amount
+		}
+	}
+	return won
+}
+
+// ----- POT FUNCTIONS -------------------------------------------------------
+
+// Return the smaller of the amount and the given top.
+func capped(amount, top uint32) uint32 {
+	if amount < top {
+		return amount
+	}
+	return top
+}
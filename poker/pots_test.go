@@ -0,0 +1,179 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func Test_can_build_side_pots(t *testing.T) {
+	for _, c := range []struct {
+		name          string
+		contributions []uint32
+		folded        []int
+		pots          string
+	}{
+		{"no all-ins", []uint32{100, 100, 100}, nil, "300:[0 1 2]"},
+		{"one short all-in", []uint32{50, 100, 100}, nil, "150:[0 1 2] 100:[1 2]"},
+		{"two all-ins", []uint32{30, 70, 100, 100}, nil, "120:[0 1 2 3] 120:[1 2 3] 60:[2 3]"},
+		{"equal all-ins share a pot", []uint32{50, 50, 100, 100}, nil, "200:[0 1 2 3] 100:[2 3]"},
+		{"folded chips stay in", []uint32{100, 50, 100}, []int{1}, "250:[0 2]"},
+		{"folded player cannot win side pot", []uint32{40, 100, 100}, []int{1}, "120:[0 2] 120:[2]"},
+		{"folded chips over the top go in last pot", []uint32{100, 100, 150}, []int{2}, "350:[0 1]"},
+		{"uncalled bet", []uint32{100, 300}, nil, "200:[0 1] 200:[1]"},
+		{"short all-in and a fold", []uint32{50, 100, 100}, []int{2}, "150:[0 1] 100:[1]"},
+		{"walk", []uint32{0, 50, 0}, []int{1, 2}, "50:[0]"},
+		{"all-ins in every seat", []uint32{10, 20, 30, 40, 50}, nil, "50:[0 1 2 3 4] 40:[1 2 3 4] 30:[2 3 4] 20:[3 4] 10:[4]"},
+		{"nothing bet", []uint32{0, 0}, nil, ""},
+	} {
+		pm := potManager(c.contributions, c.folded)
+		if s := formatPots(pm.Pots()); s != c.pots {
+			t.Fatalf("%s: expected %q but was %q", c.name, c.pots, s)
+		}
+	}
+}
+
+func Test_can_award_side_pots(t *testing.T) {
+	for _, c := range []struct {
+		name          string
+		contributions []uint32
+		folded        []int
+		contenders    []Contender
+		hiLo          bool
+		first         int
+		won           []uint32
+	}{
+		{
+			"short stack wins main pot only",
+			[]uint32{50, 100, 100}, nil,
+			[]Contender{{Hand: 1}, {Hand: 2}, {Hand: 3}}, false, 0,
+			[]uint32{150, 100, 0},
+		},
+		{
+			"big stack wins everything",
+			[]uint32{50, 100, 100}, nil,
+			[]Contender{{Hand: 3}, {Hand: 2}, {Hand: 1}}, false, 0,
+			[]uint32{0, 0, 250},
+		},
+		{
+			"side pot is split",
+			[]uint32{50, 100, 100}, nil,
+			[]Contender{{Hand: 1}, {Hand: 2}, {Hand: 2}}, false, 0,
+			[]uint32{150, 50, 50},
+		},
+		{
+			"main pot is split, side pot is not",
+			[]uint32{50, 100, 100}, nil,
+			[]Contender{{Hand: 1}, {Hand: 1}, {Hand: 2}}, false, 0,
+			[]uint32{75, 175, 0},
+		},
+		{
+			"uncalled bet goes back",
+			[]uint32{100, 300}, nil,
+			[]Contender{{Hand: 1}, {Hand: 2}}, false, 0,
+			[]uint32{200, 200},
+		},
+		{
+			"best hand folded",
+			[]uint32{100, 100, 100}, []int{0},
+			[]Contender{{Hand: 1}, {Hand: 3}, {Hand: 2}}, false, 0,
+			[]uint32{0, 0, 300},
+		},
+		{
+			"odd chip goes left of the button",
+			[]uint32{31, 100, 100, 100}, nil,
+			[]Contender{{Hand: 5}, {Hand: 1}, {Hand: 1}, {Hand: 1}}, false, 2,
+			// main pot of 124 three ways, side pot of 207 three ways
+			[]uint32{0, 41 + 69, 42 + 69, 41 + 69},
+		},
+		{
+			"hi/lo side pots",
+			[]uint32{50, 100, 100}, nil,
+			[]Contender{{Hand: 3, Low: 5}, {Hand: 1, Low: NoLow}, {Hand: 2, Low: 7}}, true, 0,
+			// main pot 150 split high/low, side pot 100 split high/low
+			[]uint32{75, 75 + 50, 50},
+		},
+		{
+			"hi/lo side pot without a low",
+			[]uint32{50, 100, 100}, nil,
+			[]Contender{{Hand: 3, Low: 5}, {Hand: 1, Low: NoLow}, {Hand: 2, Low: NoLow}}, true, 0,
+			[]uint32{75, 75 + 100, 0},
+		},
+		{
+			"three-way all-in with ties",
+			[]uint32{30, 70, 100, 100}, nil,
+			[]Contender{{Hand: 1}, {Hand: 1}, {Hand: 2}, {Hand: 2}}, false, 0,
+			[]uint32{60, 60 + 120, 30, 30},
+		},
+	} {
+		pm := potManager(c.contributions, c.folded)
+		won := pm.Award(c.contenders, c.hiLo, c.first)
+		if fmt.Sprint(won) != fmt.Sprint(c.won) {
+			t.Fatalf("%s: expected %v but was %v", c.name, c.won, won)
+		}
+	}
+}
+
+func Test_awards_account_for_every_chip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 10000; n++ {
+		seats := 2 + rng.Intn(8)
+		contributions := make([]uint32, seats)
+		contenders := make([]Contender, seats)
+		var folded []int
+		for i := range contributions {
+			contributions[i] = uint32(rng.Intn(5) * 25)
+			contenders[i] = Contender{Hand: uint64(rng.Intn(4)), Low: LowValue(rng.Intn(4))}
+			if rng.Intn(3) == 0 {
+				folded = append(folded, i)
+			}
+		}
+		if len(folded) == seats {
+			folded = folded[1:]
+		}
+		pm := potManager(contributions, folded)
+		var pots, awarded uint32
+		for _, pot := range pm.Pots() {
+			pots += pot.Amount
+		}
+		for _, amount := range pm.Award(contenders, rng.Intn(2) == 0, rng.Intn(seats)) {
+			awarded += amount
+		}
+		if pots != sum(contributions) || awarded != sum(contributions) {
+			t.Fatalf("expected %d chips in the pots and awarded but was %d and %d for %v folded %v",
+				sum(contributions), pots, awarded, contributions, folded)
+		}
+	}
+}
+
+func potManager(contributions []uint32, folded []int) *PotManager {
+	pm := NewPotManager(len(contributions))
+	for i, amount := range contributions {
+		pm.Contribute(i, amount)
+	}
+	for _, seat := range folded {
+		pm.Fold(seat)
+	}
+	return pm
+}
+
+func formatPots(pots []Pot) string {
+	s := ""
+	for i, pot := range pots {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprintf("%d:%v", pot.Amount, pot.Eligible)
+	}
+	return s
+}
+
+func sum(amounts []uint32) uint32 {
+	var total uint32
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
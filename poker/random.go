@@ -5,8 +5,46 @@ package poker
 import (
 	"crypto/rand"
 	"math/big"
+	mrand "math/rand"
 )
 
+// Source of the random numbers used to shuffle a deck.
+type RandomSource interface {
+	// Generate a random number in the range [0,max), or -1 if a number could
+	// not be generated.
+	Intn(max int) int
+}
+
+// Source backed by the operating system's cryptographically secure random
+// number generator. This is what decks use unless told otherwise, and what a
+// live server should keep using.
+var CryptoSource RandomSource = cryptoSource{}
+
+type cryptoSource struct{}
+
+func (cryptoSource) Intn(max int) int {
+	return randInt(max)
+}
+
+// Create a fast, deterministic source seeded with the given value. The same
+// seed always produces the same sequence of shuffles, which makes it suitable
+// for tests, simulations and replaying a reported deal, but it is predictable
+// and must not be used for real games.
+func NewSeededSource(seed int64) RandomSource {
+	return &seededSource{rng: mrand.New(mrand.NewSource(seed))}
+}
+
+type seededSource struct {
+	rng *mrand.Rand
+}
+
+func (src *seededSource) Intn(max int) int {
+	if max <= 0 {
+		return -1
+	}
+	return src.rng.Intn(max)
+}
+
 // randInt generates a pseudo-random number in the range [0,max). It returns
 // the generated number on success or -1 if a number could not be generated or
 // max was less than or equal to 0.
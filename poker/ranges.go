@@ -0,0 +1,447 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Number of failed attempts in a row at dealing every player a hand from
+// their range before giving up on the ranges as incompatible.
+const maxRangeRejections = 100000
+
+var (
+	EmptyRange        = fmt.Errorf("range has no hands left after removing known cards")
+	IncompatibleRange = fmt.Errorf("ranges cannot all be dealt at once")
+)
+
+// ----- PUBLIC RANGE API ----------------------------------------------------
+
+// One two-card starting hand in a range, along with how often the range
+// holds it, from just above 0 to 1.
+type Combo struct {
+	Cards  [2]Card
+	Weight float64
+}
+
+// A Hold'em range: a weighted set of two-card starting hands.
+type Range []Combo
+
+// Error describing a token of a range string that could not be parsed.
+// Offset is the position of the token in the string, counted in bytes.
+type RangeError struct {
+	Token  string
+	Offset int
+	Reason string
+}
+
+func (err *RangeError) Error() string {
+	return fmt.Sprintf("invalid range token %q at offset %d: %s", err.Token, err.Offset, err.Reason)
+}
+
+// Parse a range written in the standard notation, as a list of tokens
+// separated by commas and/or spaces. Each token is one of:
+//
+//	QQ       a pair
+//	QQ+      a pair and all higher pairs
+//	22-55    a run of pairs
+//	AKs      a suited hand (or AKo for offsuit, or AK for both)
+//	ATo+     a hand with every higher kicker up to, but not including, the
+//	         top card (i.e. ATo, AJo, AQo, AKo)
+//	76s-54s  a run of hands keeping the same gap between their ranks
+//	A2s-A5s  a run of kickers below the same top card
+//	AhKh     one specific combination of cards
+//
+// and may be followed by ":weight" to include its hands only part of the
+// time. (e.g. "AKo:0.5") If a hand appears more than once, the last weight
+// given for it wins. Returns a *RangeError for the first token that cannot be
+// parsed.
+func ParseRange(s string) (Range, error) {
+	var rng Range
+	index := make(map[[2]Card]int)
+	for _, tok := range rangeTokens(s) {
+		combos, err := parseRangeToken(tok.text)
+		if err != nil {
+			return nil, &RangeError{Token: tok.text, Offset: tok.offset, Reason: err.Error()}
+		}
+		for _, combo := range combos {
+			if i, ok := index[combo.Cards]; ok {
+				rng[i].Weight = combo.Weight
+				continue
+			}
+			index[combo.Cards] = len(rng)
+			rng = append(rng, combo)
+		}
+	}
+	return rng, nil
+}
+
+// Return the string representation of this range, one combo at a time.
+// (e.g. "AhKh,AdKd:0.5")
+func (rng Range) String() string {
+	rv := make([]string, len(rng))
+	for i, combo := range rng {
+		rv[i] = combo.Cards[0].String() + combo.Cards[1].String()
+		if combo.Weight != 1 {
+			rv[i] += ":" + strconv.FormatFloat(combo.Weight, 'g', -1, 64)
+		}
+	}
+	return strings.Join(rv, ",")
+}
+
+// Calculate each range's equity against the others in Hold'em by Monte Carlo
+// simulation. Each trial deals every player a combo from their range, in
+// proportion to its weight, and rejects the deal if any two players' cards
+// (or the board and dead cards) collide, so card removal is accounted for.
+// Only the Board, Dead, Iterations, Seed and Workers options are used.
+func CalculateRangeEquity(ranges []Range, opts EquityOptions) (EquityResult, error) {
+	if len(ranges) < 2 {
+		return EquityResult{}, TooFewPlayers
+	}
+	if len(opts.Board) > BoardSize {
+		return EquityResult{}, BoardTooLarge
+	}
+	stub, err := remainingCards(opts.Board, opts.Dead)
+	if err != nil {
+		return EquityResult{}, err
+	}
+	sim := &rangeSim{board: opts.Board, stub: stub, needed: BoardSize - len(opts.Board)}
+	for _, rng := range ranges {
+		live := liveCombos(rng, stub)
+		if len(live.combos) == 0 {
+			return EquityResult{}, EmptyRange
+		}
+		sim.ranges = append(sim.ranges, live)
+	}
+	if len(stub) < sim.needed+2*len(ranges) {
+		return EquityResult{}, EmptyDeck
+	}
+	return sim.run(opts)
+}
+
+// ----- RANGE FUNCTIONS -----------------------------------------------------
+
+// A token of a range string and where it starts.
+type rangeToken struct {
+	text   string
+	offset int
+}
+
+// Split a range string into its tokens on commas and whitespace.
+func rangeTokens(s string) []rangeToken {
+	var toks []rangeToken
+	start := -1
+	for i, r := range s + "," {
+		if r == ',' || unicode.IsSpace(r) {
+			if start >= 0 {
+				toks = append(toks, rangeToken{text: s[start:i], offset: start})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return toks
+}
+
+// Parse one token of a range string into its combos.
+func parseRangeToken(tok string) ([]Combo, error) {
+	weight := 1.0
+	if i := strings.IndexByte(tok, ':'); i >= 0 {
+		w, err := strconv.ParseFloat(tok[i+1:], 64)
+		if err != nil || w <= 0 || w > 1 {
+			return nil, fmt.Errorf("weight must be a number greater than 0 and at most 1")
+		}
+		tok, weight = tok[:i], w
+	}
+	var combos []Combo
+	add := func(cards [][2]Card) {
+		for _, c := range cards {
+			// keep the higher card first so each combo has one spelling
+			if c[1].Rank() > c[0].Rank() || (c[1].Rank() == c[0].Rank() && c[1].Suit() > c[0].Suit()) {
+				c[0], c[1] = c[1], c[0]
+			}
+			combos = append(combos, Combo{Cards: c, Weight: weight})
+		}
+	}
+
+	// a specific combo, such as AhKh
+	if len(tok) == 4 {
+		a, aok := parseRankSuit(tok[0], tok[1])
+		b, bok := parseRankSuit(tok[2], tok[3])
+		if aok && bok {
+			if a == b {
+				return nil, fmt.Errorf("card %s appears twice", a)
+			}
+			add([][2]Card{{a, b}})
+			return combos, nil
+		}
+	}
+	if i := strings.IndexByte(tok, '-'); i >= 0 {
+		lo, hi, err := parseRangeSpan(tok[:i], tok[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case hi.pair():
+			for r := lo.high; r <= hi.high; r++ {
+				add(hi.expand(r, r))
+			}
+		case lo.high == hi.high:
+			for r := lo.low; r <= hi.low; r++ {
+				add(hi.expand(hi.high, r))
+			}
+		default:
+			gap := hi.high - hi.low
+			for r := lo.high; r <= hi.high; r++ {
+				add(hi.expand(r, r-gap))
+			}
+		}
+		return combos, nil
+	}
+	plus := strings.HasSuffix(tok, "+")
+	hand, err := parseRangeHand(strings.TrimSuffix(tok, "+"))
+	if err != nil {
+		return nil, err
+	}
+	if !plus {
+		add(hand.expand(hand.high, hand.low))
+		return combos, nil
+	}
+	if hand.pair() {
+		for r := hand.high; r <= Ace; r++ {
+			add(hand.expand(r, r))
+		}
+		return combos, nil
+	}
+	for r := hand.low; r < hand.high; r++ {
+		add(hand.expand(hand.high, r))
+	}
+	return combos, nil
+}
+
+// A starting hand in range notation with its suits left open.
+type rangeHand struct {
+	high, low int
+	// 's' for suited, 'o' for offsuit or 0 for either
+	suited byte
+}
+
+// Is this hand a pocket pair?
+func (hand rangeHand) pair() bool {
+	return hand.high == hand.low
+}
+
+// Return every combo of the given ranks matching this hand's suitedness.
+func (hand rangeHand) expand(high, low int) [][2]Card {
+	var rv [][2]Card
+	suits := []int{Club, Diamond, Heart, Spade}
+	for i, s1 := range suits {
+		for j, s2 := range suits {
+			if high == low && j <= i {
+				continue
+			}
+			if (hand.suited == 's' && s1 != s2) || (hand.suited == 'o' && s1 == s2) {
+				continue
+			}
+			rv = append(rv, [2]Card{NewCard(high, s1), NewCard(low, s2)})
+		}
+	}
+	return rv
+}
+
+// Parse a starting hand such as "AKs", "T9o", "QJ" or "77".
+func parseRangeHand(s string) (rangeHand, error) {
+	if len(s) < 2 || len(s) > 3 {
+		return rangeHand{}, fmt.Errorf("expected two ranks and an optional s or o")
+	}
+	high, ok := parseRank(s[0])
+	if !ok {
+		return rangeHand{}, fmt.Errorf("unknown rank %q", s[0])
+	}
+	low, ok := parseRank(s[1])
+	if !ok {
+		return rangeHand{}, fmt.Errorf("unknown rank %q", s[1])
+	}
+	if low > high {
+		high, low = low, high
+	}
+	hand := rangeHand{high: high, low: low}
+	if len(s) == 3 {
+		switch s[2] {
+		case 's', 'o':
+			hand.suited = s[2]
+		default:
+			return rangeHand{}, fmt.Errorf("expected s or o but found %q", s[2])
+		}
+		if hand.pair() {
+			return rangeHand{}, fmt.Errorf("pairs cannot be suited or offsuit")
+		}
+	}
+	return hand, nil
+}
+
+// Parse both ends of a span such as "76s-54s" or "A2s-A5s", returning the
+// lower end first. The two ends must be the same kind of hand and either
+// share their top card or keep the same gap between their ranks.
+func parseRangeSpan(a, b string) (rangeHand, rangeHand, error) {
+	lo, err := parseRangeHand(a)
+	if err != nil {
+		return lo, lo, err
+	}
+	hi, err := parseRangeHand(b)
+	if err != nil {
+		return lo, hi, err
+	}
+	if lo.high > hi.high || (lo.high == hi.high && lo.low > hi.low) {
+		lo, hi = hi, lo
+	}
+	switch {
+	case lo.suited != hi.suited || lo.pair() != hi.pair():
+		return lo, hi, fmt.Errorf("both ends of a span must be the same kind of hand")
+	case lo.high == hi.high && lo.pair():
+		return lo, hi, fmt.Errorf("span of pairs must cover more than one rank")
+	case lo.high != hi.high && hi.high-hi.low != lo.high-lo.low:
+		return lo, hi, fmt.Errorf("both ends of a span must share a top card or keep the same gap")
+	}
+	return lo, hi, nil
+}
+
+// A range with the combos blocked by known cards removed, ready for weighted
+// sampling.
+type liveRange struct {
+	combos []Combo
+	// running total of the combos' weights
+	cumulative []float64
+}
+
+// Remove the combos using cards not in the stub, and total up the weights.
+func liveCombos(rng Range, stub []Card) liveRange {
+	avail := make(map[Card]bool, len(stub))
+	for _, card := range stub {
+		avail[card] = true
+	}
+	var live liveRange
+	total := 0.0
+	for _, combo := range rng {
+		if !avail[combo.Cards[0]] || !avail[combo.Cards[1]] {
+			continue
+		}
+		total += combo.Weight
+		live.combos = append(live.combos, combo)
+		live.cumulative = append(live.cumulative, total)
+	}
+	return live
+}
+
+// Pick a combo from this range in proportion to its weight.
+func (live liveRange) sample(rng *rand.Rand) [2]Card {
+	x := rng.Float64() * live.cumulative[len(live.cumulative)-1]
+	i := sort.SearchFloat64s(live.cumulative, x)
+	if i == len(live.combos) {
+		i--
+	}
+	return live.combos[i].Cards
+}
+
+// State shared by the workers of a range equity calculation.
+type rangeSim struct {
+	ranges []liveRange
+	board  []Card
+	stub   []Card
+	needed int
+}
+
+// Run the simulation across the workers and combine their tallies.
+func (sim *rangeSim) run(opts EquityOptions) (EquityResult, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+	tallies := make([]*equityTally, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		tallies[w] = newTally(len(sim.ranges))
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			n := iterations / workers
+			if w < iterations%workers {
+				n++
+			}
+			errs[w] = sim.simulate(tallies[w], rand.New(rand.NewSource(opts.Seed+int64(w))), n)
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return EquityResult{}, err
+		}
+	}
+	return summarize(tallies, len(sim.ranges), false), nil
+}
+
+// Deal and evaluate n trials, or return IncompatibleRange if the players'
+// ranges keep colliding with each other.
+func (sim *rangeSim) simulate(tally *equityTally, rng *rand.Rand, n int) error {
+	hands := make([][]Card, len(sim.ranges))
+	for i := range hands {
+		hands[i] = make([]Card, 2)
+	}
+	eq := &equitySim{hands: hands}
+	scratch := eq.newScratch()
+	board := make([]Card, BoardSize)
+	copy(board, sim.board)
+	used := make(map[Card]bool)
+	for ; n > 0; n-- {
+		if !sim.deal(rng, hands, used) {
+			return IncompatibleRange
+		}
+		for i := len(sim.board); i < BoardSize; {
+			card := sim.stub[rng.Intn(len(sim.stub))]
+			if !used[card] {
+				used[card] = true
+				board[i] = card
+				i++
+			}
+		}
+		eq.score(tally, board, scratch)
+	}
+	return nil
+}
+
+// Deal every player a combo from their range with no cards in common,
+// recording the cards dealt in used. Returns false if no such deal turned up.
+func (sim *rangeSim) deal(rng *rand.Rand, hands [][]Card, used map[Card]bool) bool {
+	for attempt := 0; attempt < maxRangeRejections; attempt++ {
+		for card := range used {
+			delete(used, card)
+		}
+		ok := true
+		for i, live := range sim.ranges {
+			combo := live.sample(rng)
+			if used[combo[0]] || used[combo[1]] {
+				ok = false
+				break
+			}
+			used[combo[0]], used[combo[1]] = true, true
+			hands[i][0], hands[i][1] = combo[0], combo[1]
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
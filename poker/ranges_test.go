@@ -0,0 +1,108 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_can_parse_range_notation(t *testing.T) {
+	for s, n := range map[string]int{
+		"QQ":                   6,
+		"QQ+":                  18,
+		"22-44":                18,
+		"AKs":                  4,
+		"AKo":                  12,
+		"AK":                   16,
+		"ATo+":                 48,
+		"76s-54s":              12,
+		"A2s-A5s":              16,
+		"AhKh":                 1,
+		"QQ+, AKs":             22,
+		"AKs AhKh":             4,
+		"QQ+,AKs,ATo+,76s-54s": 82,
+	} {
+		rng := parseRange(t, s)
+		if len(rng) != n {
+			t.Fatalf("expected %d combos in %q but was %d", n, s, len(rng))
+		}
+	}
+}
+
+func Test_range_weights_apply_to_token(t *testing.T) {
+	rng := parseRange(t, "AKs:0.5, QQ")
+	for _, combo := range rng {
+		expected := 1.0
+		if combo.Cards[0].Rank() == Ace {
+			expected = 0.5
+		}
+		if combo.Weight != expected {
+			t.Fatalf("expected weight %f for %s but was %f", expected, PrintHand(combo.Cards[:]), combo.Weight)
+		}
+	}
+	// a later token overrides the weight of an earlier one
+	rng = parseRange(t, "AKs, KhAh:0.25")
+	if len(rng) != 4 || !strings.Contains(rng.String(), "AhKh:0.25") {
+		t.Fatalf("expected AhKh at 0.25 but was %s", rng)
+	}
+}
+
+func Test_range_errors_point_at_token(t *testing.T) {
+	for s, tok := range map[string]string{
+		"QQ+, AXs":  "AXs",
+		"AKs,  QQs": "QQs",
+		"76s-54o":   "76s-54o",
+		"76s-42s":   "76s-42s",
+		"AKs:2":     "AKs:2",
+		"AhAh":      "AhAh",
+		"JJ, T":     "T",
+	} {
+		_, err := ParseRange(s)
+		rerr, ok := err.(*RangeError)
+		if !ok {
+			t.Fatalf("expected RangeError for %q but was %v", s, err)
+		}
+		if rerr.Token != tok || s[rerr.Offset:rerr.Offset+len(tok)] != tok {
+			t.Fatalf("expected error at %q in %q but was %v", tok, s, err)
+		}
+	}
+}
+
+func Test_range_equity_accounts_for_card_removal(t *testing.T) {
+	// aces against a range of kings or ace-king: the ace-king combos are
+	// mostly blocked, so the aces do better than against a random mix
+	aces := parseRange(t, "AsAd")
+	villain := parseRange(t, "KK, AK")
+	result, err := CalculateRangeEquity([]Range{aces, villain}, EquityOptions{Iterations: 20000, Seed: 7, Workers: 2})
+	if err != nil {
+		t.Fatalf("cannot calculate range equity: %v", err)
+	}
+	if result.Boards != 20000 {
+		t.Fatalf("expected 20000 boards but was %d", result.Boards)
+	}
+	expectClose(t, result.Players[0].Share+result.Players[1].Share, 1)
+	if share := result.Players[0].Share; share < 0.80 || share > 0.88 {
+		t.Fatalf("expected about 84%% equity but was %.3f", share)
+	}
+}
+
+func Test_range_equity_rejects_blocked_range(t *testing.T) {
+	aces := parseRange(t, "AsAd")
+	_, err := CalculateRangeEquity([]Range{aces, parseRange(t, "AsKs")}, EquityOptions{Dead: makeHand([]string{"Ks"})})
+	if err != EmptyRange {
+		t.Fatalf("expected EmptyRange but was %v", err)
+	}
+	_, err = CalculateRangeEquity([]Range{aces, parseRange(t, "AsKs")}, EquityOptions{Iterations: 10})
+	if err != IncompatibleRange {
+		t.Fatalf("expected IncompatibleRange but was %v", err)
+	}
+}
+
+func parseRange(t *testing.T, s string) Range {
+	rng, err := ParseRange(s)
+	if err != nil {
+		t.Fatalf("cannot parse %q: %v", s, err)
+	}
+	return rng
+}
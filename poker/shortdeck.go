@@ -0,0 +1,112 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+)
+
+var InvalidShortDeckCard = fmt.Errorf("card is not in a short deck")
+
+// ----- PUBLIC SHORT DECK EVALUATION API ------------------------------------
+
+// Equivalence value of a hand for Short Deck (6+) Hold'em. With the deuces
+// through fives gone, flushes are harder to make than full houses and so
+// beat them, and A-9-8-7-6 is the lowest straight. Otherwise hands rank as
+// they do for high. The lower the value, the stronger the hand, and two hands
+// with the same value are tied.
+type ShortDeckValue uint16
+
+// Report the ranking of this value. (i.e. StraightFlush, Flush, etc)
+func (val ShortDeckValue) Rank() int {
+	if val > shortFlushes && val <= shortFullHouses {
+		return FullHouse
+	}
+	if val > 166 && val <= shortFlushes {
+		return Flush
+	}
+	return handRank(uint16(val))
+}
+
+// Does this value beat the other one?
+func (val ShortDeckValue) Beats(other ShortDeckValue) bool {
+	return val < other
+}
+
+// Determine the given hand's Short Deck value. Returns InvalidHandSize
+// unless the hand contains 5, 6 or 7 cards, or InvalidShortDeckCard if any
+// card is below a six.
+func EvaluateShortDeck(hand []Card) (ShortDeckValue, error) {
+	if len(hand) < 5 || len(hand) > 7 {
+		return 0, InvalidHandSize
+	}
+	for _, card := range hand {
+		if card == Joker || card.Rank() < Six {
+			return 0, InvalidShortDeckCard
+		}
+	}
+	switch len(hand) {
+	case 5:
+		return evalShortDeckFive(hand), nil
+	case 6:
+		return evalShortDeckSubhands(hand, perm6[:]), nil
+	}
+	return evalShortDeckSubhands(hand, perm7[:]), nil
+}
+
+// ----- SHORT DECK EVALUATION FUNCTIONS -------------------------------------
+
+// Last values of the flushes and the full houses once the flushes have moved
+// above the full houses. There are 1277 flushes and 156 full houses.
+const (
+	shortFlushes    = 166 + 1277
+	shortFullHouses = shortFlushes + 156
+)
+
+// Rank bitmask of A-9-8-7-6.
+const shortWheelRanks = 0x10F0
+
+// Generate the best Short Deck value among the given subhands.
+func evalShortDeckSubhands(hand []Card, perms [][5]uint32) ShortDeckValue {
+	var best ShortDeckValue = 0xFFFF
+	subhand := []Card{0, 0, 0, 0, 0}
+	for _, perm := range perms {
+		for j := 0; j < 5; j++ {
+			subhand[j] = hand[perm[j]]
+		}
+		if q := evalShortDeckFive(subhand); q < best {
+			best = q
+		}
+	}
+	return best
+}
+
+// Generate the Short Deck value of a 5-card hand from its value for high:
+// A-9-8-7-6 takes the place of the wheel, which a short deck cannot make,
+// and flushes and full houses swap places.
+func evalShortDeckFive(hand []Card) ShortDeckValue {
+	var bits uint32
+	suits := uint32(0xF000)
+	for _, card := range hand {
+		bits |= uint32(card) >> 16
+		suits &= uint32(card)
+	}
+	var val uint16
+	switch {
+	case bits == shortWheelRanks && suits != 0:
+		// five-high straight flush
+		val = 10
+	case bits == shortWheelRanks:
+		// five-high straight
+		val = 1609
+	default:
+		val = eval5CardHand(hand)
+	}
+	switch handRank(val) {
+	case Flush:
+		val -= 156
+	case FullHouse:
+		val += 1277
+	}
+	return ShortDeckValue(val)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_flush_beats_full_house_in_short_deck(t *testing.T) {
+	flush := shortDeck(t, makeHand([]string{"6h", "8h", "9h", "Jh", "Kh"}))
+	boat := shortDeck(t, makeHand([]string{"Ac", "Ad", "Ah", "Ks", "Kd"}))
+	if flush.Rank() != Flush || boat.Rank() != FullHouse {
+		t.Fatalf("expected %d and %d but was %d and %d", Flush, FullHouse, flush.Rank(), boat.Rank())
+	}
+	if !flush.Beats(boat) {
+		t.Fatalf("expected flush to beat full house")
+	}
+	quads := shortDeck(t, makeHand([]string{"6c", "6d", "6h", "6s", "7d"}))
+	if !quads.Beats(flush) {
+		t.Fatalf("expected quads to beat flush")
+	}
+	straight := shortDeck(t, makeHand([]string{"Ac", "Kd", "Qh", "Js", "Td"}))
+	if !boat.Beats(straight) {
+		t.Fatalf("expected full house to beat straight")
+	}
+}
+
+func Test_can_detect_short_deck_wheel(t *testing.T) {
+	wheel := shortDeck(t, makeHand([]string{"Ac", "6d", "7h", "8s", "9d"}))
+	if wheel.Rank() != Straight {
+		t.Fatalf("expected %d but was %d", Straight, wheel.Rank())
+	}
+	sixHigh := shortDeck(t, makeHand([]string{"Tc", "6d", "7h", "8s", "9d"}))
+	if !sixHigh.Beats(wheel) {
+		t.Fatalf("expected T-high straight to beat A-9-8-7-6")
+	}
+	trips := shortDeck(t, makeHand([]string{"Ac", "Ad", "Ah", "8s", "9d"}))
+	if !wheel.Beats(trips) {
+		t.Fatalf("expected A-9-8-7-6 to beat trips")
+	}
+	steel := shortDeck(t, makeHand([]string{"As", "6s", "7s", "8s", "9s"}))
+	if steel.Rank() != StraightFlush {
+		t.Fatalf("expected %d but was %d", StraightFlush, steel.Rank())
+	}
+	quads := shortDeck(t, makeHand([]string{"Ac", "Ad", "Ah", "As", "Kd"}))
+	if !steel.Beats(quads) {
+		t.Fatalf("expected straight flush to beat quads")
+	}
+}
+
+func Test_can_find_best_short_deck_hand_in_seven_cards(t *testing.T) {
+	// only ace-high for high, but a straight here
+	hand := makeHand([]string{"As", "Kc", "9d", "8s", "Qc", "7h", "6d"})
+	val := shortDeck(t, hand)
+	if val.Rank() != Straight {
+		t.Fatalf("expected %d but was %d", Straight, val.Rank())
+	}
+	flush := shortDeck(t, makeHand([]string{"Kh", "Kd", "9h", "9c", "7h", "6h", "Th"}))
+	if flush.Rank() != Flush {
+		t.Fatalf("expected %d but was %d", Flush, flush.Rank())
+	}
+	if _, err := EvaluateShortDeck(makeHand([]string{"Ac", "Ad", "5h", "8s", "9d"})); err != InvalidShortDeckCard {
+		t.Fatalf("expected InvalidShortDeckCard but was %v", err)
+	}
+}
+
+func Test_short_deck_ranks_every_hand(t *testing.T) {
+	deck := NewShortDeck().cards
+	counts := make(map[int]int)
+	hand := make([]Card, 5)
+	var visit func(start, n int)
+	visit = func(start, n int) {
+		if n == 5 {
+			counts[shortDeck(t, hand).Rank()]++
+			return
+		}
+		for i := start; i < len(deck); i++ {
+			hand[n] = deck[i]
+			visit(i+1, n+1)
+		}
+	}
+	visit(0, 0)
+	// six straights in each suit, from A-9-8-7-6 up to the royal
+	for rank, want := range map[int]int{StraightFlush: 24, FourOfAKind: 288, FullHouse: 1728, Flush: 480, Straight: 6120} {
+		if counts[rank] != want {
+			t.Fatalf("expected %d hands of rank %d but was %d", want, rank, counts[rank])
+		}
+	}
+}
+
+func shortDeck(t *testing.T, hand []Card) ShortDeckValue {
+	val, err := EvaluateShortDeck(hand)
+	if err != nil {
+		t.Fatalf("cannot evaluate %s: %v", PrintHand(hand), err)
+	}
+	return val
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+// ----- PUBLIC SHOWDOWN API -------------------------------------------------
+
+// Record of a player's hand at showdown. As with the evaluators, lower values
+// win. Hand holds the value under the game's main ranking (i.e. a HandValue
+// for high games, a LowValue for Razz, etc) and Low holds the eight-or-better
+// low in hi/lo games, or NoLow if the player has no qualifying low.
+type Contender struct {
+	Hand uint64
+	Low  LowValue
+}
+
+// Value for a hand that cannot win anything at showdown.
+const NoHand uint64 = 0xFFFFFFFFFFFFFFFF
+
+// Split a pot between the players contesting it at showdown, returning the
+// amount each of them wins. Contenders must be given in seat order starting
+// to the left of the button, as that is the order odd chips are handed out
+// in. In hi/lo games the pot is halved between the best high and the best
+// low, with any odd chip going to the high; if nobody qualifies for low the
+// high hand scoops the whole pot. Tied hands split their share evenly.
+func SplitPot(pot uint32, contenders []Contender, hiLo bool) []uint32 {
+	won := make([]uint32, len(contenders))
+	if len(contenders) == 0 {
+		return won
+	}
+	lows := lowWinners(contenders)
+	if !hiLo || len(lows) == 0 {
+		award(won, pot, highWinners(contenders))
+		return won
+	}
+	low := pot / 2
+	award(won, pot-low, highWinners(contenders))
+	award(won, low, lows)
+	return won
+}
+
+// ----- SHOWDOWN FUNCTIONS --------------------------------------------------
+
+// Find the indices of the contenders holding the best main hand.
+func highWinners(contenders []Contender) []int {
+	var winners []int
+	for i, c := range contenders {
+		switch {
+		case len(winners) == 0 || c.Hand < contenders[winners[0]].Hand:
+			winners = append(winners[:0], i)
+		case c.Hand == contenders[winners[0]].Hand:
+			winners = append(winners, i)
+		}
+	}
+	return winners
+}
+
+// Find the indices of the contenders holding the best qualifying low, if any.
+func lowWinners(contenders []Contender) []int {
+	var winners []int
+	for i, c := range contenders {
+		if c.Low == NoLow {
+			continue
+		}
+		switch {
+		case len(winners) == 0 || c.Low < contenders[winners[0]].Low:
+			winners = append(winners[:0], i)
+		case c.Low == contenders[winners[0]].Low:
+			winners = append(winners, i)
+		}
+	}
+	return winners
+}
+
+// Divide amount evenly between the given winners, handing any odd chips out
+// one at a time in the order the winners are given.
+func award(won []uint32, amount uint32, winners []int) {
+	n := uint32(len(winners))
+	share, odd := amount/n, amount%n
+	for i, w := range winners {
+		won[w] += share
+		if uint32(i) < odd {
+			won[w]++
+		}
+	}
+}
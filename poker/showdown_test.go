@@ -0,0 +1,53 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+func Test_best_high_scoops_without_qualifying_low(t *testing.T) {
+	won := SplitPot(100, []Contender{{Hand: 300, Low: NoLow}, {Hand: 200, Low: NoLow}}, true)
+	expectWinnings(t, won, 0, 100)
+}
+
+func Test_pot_is_split_between_high_and_low(t *testing.T) {
+	won := SplitPot(101, []Contender{{Hand: 300, Low: 5}, {Hand: 200, Low: NoLow}}, true)
+	// the odd chip goes to the high half
+	expectWinnings(t, won, 50, 51)
+}
+
+func Test_same_player_can_scoop_high_and_low(t *testing.T) {
+	won := SplitPot(100, []Contender{{Hand: 300, Low: 9}, {Hand: 200, Low: 5}}, true)
+	expectWinnings(t, won, 0, 100)
+}
+
+func Test_tied_low_splits_the_low_half(t *testing.T) {
+	won := SplitPot(120, []Contender{{Hand: 100, Low: NoLow}, {Hand: 300, Low: 5}, {Hand: 400, Low: 5}}, true)
+	expectWinnings(t, won, 60, 30, 30)
+}
+
+func Test_high_winner_tied_for_low_is_quartered(t *testing.T) {
+	// the high and half the low, three quarters of the pot in all
+	won := SplitPot(120, []Contender{{Hand: 100, Low: 5}, {Hand: 300, Low: 5}, {Hand: 400, Low: NoLow}}, true)
+	expectWinnings(t, won, 90, 30, 0)
+}
+
+func Test_odd_chips_go_to_first_winner_left_of_button(t *testing.T) {
+	won := SplitPot(11, []Contender{{Hand: 300}, {Hand: 100}, {Hand: 100}, {Hand: 100}}, false)
+	expectWinnings(t, won, 0, 4, 4, 3)
+	won = SplitPot(11, []Contender{{Hand: 300, Low: 7}, {Hand: 100, Low: NoLow}, {Hand: 100, Low: 7}}, true)
+	// high gets 6 split 3/3, low gets 5 split 3/2
+	expectWinnings(t, won, 3, 3, 5)
+}
+
+func expectWinnings(t *testing.T, won []uint32, expected ...uint32) {
+	if len(won) != len(expected) {
+		t.Fatalf("expected %v but was %v", expected, won)
+	}
+	for i := range won {
+		if won[i] != expected[i] {
+			t.Fatalf("expected %v but was %v", expected, won)
+		}
+	}
+}
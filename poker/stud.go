@@ -0,0 +1,310 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"sort"
+)
+
+// Most players a stud game can seat. Eight players take 48 cards by sixth
+// street, which leaves enough to deal the river as a community card.
+const MaxStudSeats = 8
+
+// Cards each player takes up to sixth street.
+const studCardsPerPlayer = 6
+
+// ----- PUBLIC STUD GAME API ------------------------------------------------
+
+// Record for seven card stud games. (7Stud, 7StudHiLo and Razz)
+type StudGame struct {
+	table
+	game     GameType
+	up       [][]Card
+	board    []Card
+	maxSeats int
+}
+
+// Create a new stud game instance. Stud has no blinds, so the small blind
+// sets the bring-in and the big blind the bet size for third and fourth
+// streets, which FixedLimit games double for the later streets. Every player
+// posts the ante. The game seats at most MaxStudSeats players, and fewer if
+// the deck, which must be complete, is too small to deal each of them six
+// cards and a community river. Returns TableFull if there are too many
+// players.
+func NewStudGame(players []Player, deck Deck, game GameType, limit GameLimit, blinds Blinds, maxRaises int) (*StudGame, error) {
+	maxSeats := (deck.Remaining() - 1) / studCardsPerPlayer
+	if maxSeats > MaxStudSeats {
+		maxSeats = MaxStudSeats
+	}
+	if len(players) > maxSeats {
+		return nil, TableFull
+	}
+	return &StudGame{
+		table:    newTable(players, deck, limit, blinds, maxRaises),
+		game:     game,
+		maxSeats: maxSeats,
+	}, nil
+}
+
+// Add a player to the first empty seat. Returns TableFull if the game
+// already seats as many players as it can deal to.
+func (game *StudGame) AddPlayer(player Player) error {
+	seated := 0
+	for _, p := range game.players {
+		if p != nil {
+			seated++
+		}
+	}
+	if seated >= game.maxSeats {
+		return TableFull
+	}
+	return game.table.AddPlayer(player)
+}
+
+// 7Stud/7StudHiLo/Razz:
+//  1. Post antes, if applicable
+//  2. Deal 2 private cards and 1 public card
+//  3. Player with lowest door card posts bring-in (highest in Razz)
+//  4. Round of betting at lower limit, action starts to that player's left
+//  5. Deal 1 public card
+//  6. Round of betting at lower limit, action starts on player with strongest exposed board and continues to their left
+//  7. Deal 1 public card
+//  8. Round of betting at higher limit, action starts on player with strongest exposed board and continues to their left
+//  9. Deal 1 public card
+//  10. Round of betting at higher limit, action starts on player with strongest exposed board and continues to their left
+//  11. Deal 1 private card or shared card if insufficient cards left in deck to deal to every player remaining
+//  12. Round of betting at higher limit, action starts on player with strongest exposed board and continues to their left
+//  13. Showdown
+func (game *StudGame) Play() {
+	if !game.init() {
+		return
+	}
+	// third street
+	game.DealPrivate(2)
+	game.DealPublic(1)
+	first := game.bringIn()
+	if game.blinds.small > 0 {
+		// the bring-in is the player's action; calling it ends the round
+		game.post(first, game.blinds.small, true)
+		game.acted[first] = true
+		first++
+	}
+	game.betting(game.smallBet(), first%len(game.players))
+	// fourth street
+	if game.live() > 1 {
+		game.DealPublic(1)
+		game.betting(game.smallBet(), game.bestShowing())
+	}
+	// fifth and sixth streets
+	for street := 5; street <= 6 && game.live() > 1; street++ {
+		game.DealPublic(1)
+		game.betting(game.bigBet(), game.bestShowing())
+	}
+	// river
+	if game.live() > 1 {
+		if game.deck.Remaining() < game.live() {
+			game.DealShared(1)
+		} else {
+			game.DealPrivate(1)
+		}
+		game.betting(game.bigBet(), game.bestShowing())
+	}
+	game.showdown()
+}
+
+// Deal the given number of cards to each player privately (i.e. face down)
+// Deals player to left of button first, ending with dealing to button. Seats
+// not in the hand are skipped.
+func (game *StudGame) DealPrivate(cards int) {
+	game.dealPrivate(cards)
+}
+
+// Deal the given number of cards to the shared community card board. Stud
+// only does this when the deck runs too short to deal everyone their river.
+func (game *StudGame) DealShared(cards int) {
+	dealt := make([]Card, cards)
+	for i := 0; i < cards; i++ {
+		dealt[i] = game.deck.MustDeal()
+	}
+	game.board = append(game.board, dealt...)
+	for _, player := range game.players {
+		if player != nil {
+			player.DealShared(dealt)
+		}
+	}
+}
+
+// Deal the given number of cards to each player face up, in the same order
+// as DealPrivate.
+func (game *StudGame) DealPublic(cards int) {
+	cnt := len(game.players)
+	for i := 0; i < cards; i++ {
+		for j := 1; j <= cnt; j++ {
+			p := (game.dealer + j) % cnt
+			if game.folded[p] {
+				continue
+			}
+			card := game.deck.MustDeal()
+			game.hands[p] = append(game.hands[p], card)
+			game.up[p] = append(game.up[p], card)
+			game.players[p].DealPublic(card)
+		}
+	}
+}
+
+// ----- STUD GAME FUNCTIONS -------------------------------------------------
+
+// Prepare this game for a new round of play. Returns false if there are not
+// enough players to deal a hand.
+func (game *StudGame) init() bool {
+	if !game.startHand() {
+		return false
+	}
+	for len(game.up) < len(game.players) {
+		game.up = append(game.up, nil)
+	}
+	for p := range game.players {
+		game.up[p] = game.up[p][:0]
+		// without blinds there is nothing to owe for sitting out
+		game.missedSmall[p] = false
+		game.missedBig[p] = false
+	}
+	game.board = game.board[:0]
+	game.postAntes()
+	return true
+}
+
+// Is this game played for low only?
+func (game *StudGame) isLow() bool {
+	return game.game == Razz
+}
+
+// Is this game split between the best high and the best low?
+func (game *StudGame) isHiLo() bool {
+	return game.game == SevenStudHL
+}
+
+// Report the seat that must bring in: the player showing the lowest door
+// card, aces high, or the highest, aces low, in Razz. Ties are broken by
+// suit, with clubs lowest and spades highest.
+func (game *StudGame) bringIn() int {
+	seat, worst := -1, 0
+	for p := range game.players {
+		if game.folded[p] {
+			continue
+		}
+		door := game.up[p][0]
+		val := door.Rank()*4 + suitIndex(door.Suit())
+		if game.isLow() {
+			val = -(int(lowRank(door.Rank()))*4 + suitIndex(door.Suit()))
+		}
+		if seat < 0 || val < worst {
+			seat, worst = p, val
+		}
+	}
+	return seat
+}
+
+// Report the seat showing the strongest exposed board, which acts first
+// from fourth street on. Only pairs, trips and quads count, so boards are
+// compared on those and then on their cards from the top down, aces low in
+// Razz, where the lowest board is the strongest. Tied boards go to the
+// first of them to the left of the button.
+func (game *StudGame) bestShowing() int {
+	cnt := len(game.players)
+	seat := -1
+	var best []int
+	for j := 1; j <= cnt; j++ {
+		p := (game.dealer + j) % cnt
+		if game.folded[p] {
+			continue
+		}
+		key := game.showing(game.up[p])
+		if seat < 0 || compareShowing(key, best) > 0 {
+			seat, best = p, key
+		}
+	}
+	return seat
+}
+
+// Return a key for the given exposed cards that is greater the stronger the
+// board. It holds the size of each group of same-ranked cards, largest
+// first, followed by each group's rank, so a pair beats any unpaired board.
+// For Razz the whole key is negated, so that the best low is the greatest.
+func (game *StudGame) showing(cards []Card) []int {
+	var counts [13]int
+	for _, card := range cards {
+		if game.isLow() {
+			counts[lowRank(card.Rank())]++
+		} else {
+			counts[card.Rank()]++
+		}
+	}
+	var ranks []int
+	for r := 12; r >= 0; r-- {
+		if counts[r] > 0 {
+			ranks = append(ranks, r)
+		}
+	}
+	sort.SliceStable(ranks, func(i, j int) bool { return counts[ranks[i]] > counts[ranks[j]] })
+	key := make([]int, 0, 2*len(ranks))
+	for _, r := range ranks {
+		key = append(key, counts[r])
+	}
+	key = append(key, ranks...)
+	if game.isLow() {
+		for i := range key {
+			key[i] = -key[i]
+		}
+	}
+	return key
+}
+
+// Award the main pot and any side pots to the best hand(s) eligible for
+// each. Folded players cannot win.
+func (game *StudGame) showdown() {
+	contenders := make([]Contender, len(game.players))
+	for p := range game.players {
+		if game.folded[p] {
+			contenders[p] = Contender{Hand: NoHand, Low: NoLow}
+			continue
+		}
+		contenders[p] = game.evaluate(game.hands[p])
+	}
+	game.award(contenders, game.isHiLo())
+}
+
+// Evaluate the given cards along with any community river card. Razz hands
+// are ranked as ace-to-five lows, and hi/lo hands also for an eight or
+// better low. A hand that cannot be evaluated can win nothing.
+func (game *StudGame) evaluate(hand []Card) Contender {
+	cards := make([]Card, 0, len(hand)+len(game.board))
+	cards = append(append(cards, hand...), game.board...)
+	if game.isLow() {
+		low, err := EvaluateForLowA5(cards, false)
+		if err != nil {
+			return Contender{Hand: NoHand, Low: NoLow}
+		}
+		return Contender{Hand: uint64(low), Low: NoLow}
+	}
+	high, err := EvaluateHand(cards)
+	if err != nil {
+		return Contender{Hand: NoHand, Low: NoLow}
+	}
+	low := NoLow
+	if game.isHiLo() {
+		low, _ = EvaluateForLowA5(cards, true)
+	}
+	return Contender{Hand: uint64(high), Low: low}
+}
+
+// Compare two exposed board keys, returning a positive number if the first
+// is stronger, a negative one if the second is, and 0 if they are tied.
+func compareShowing(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
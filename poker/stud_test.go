@@ -0,0 +1,124 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"testing"
+)
+
+var _ Game = &StudGame{}
+
+func Test_bring_in_and_first_to_act(t *testing.T) {
+	for _, c := range []struct {
+		game           GameType
+		bringIn, first int
+	}{
+		// deuce of clubs brings in; the pair of deuces acts first
+		{SevenStud, 1, 0},
+		// king brings in; the unpaired 3-2 acts first
+		{Razz, 2, 1},
+	} {
+		players := []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}}
+		// seat 1 has the button, so seat 2 is dealt to first
+		deck := stackedDeck(t, "Ah Kc Qc Ad Kd Qd Kh 2d 2c 9s 2h 3c Ts Js Qs Ks As 3s 4s")
+		game := newStudGame(t, players, deck, c.game)
+		game.init()
+		game.DealPrivate(2)
+		game.DealPublic(1)
+		if game.bringIn() != c.bringIn {
+			t.Fatalf("expected seat %d to bring in but was %d", c.bringIn, game.bringIn())
+		}
+		game.DealPublic(1)
+		if game.bestShowing() != c.first {
+			t.Fatalf("expected seat %d to act first but was %d", c.first, game.bestShowing())
+		}
+	}
+}
+
+func Test_calling_the_bring_in_ends_third_street(t *testing.T) {
+	players := []*testPlayer{{chips: 1000}, {chips: 1000}, {chips: 1000}}
+	// seat 1 has the button and brings in with the deuce of clubs
+	deck := stackedDeck(t, "Ah Kc Qc Ad Kd Qd Kh 2d 2c 9s 2h 3c Ts Js Qs Ks As 3s 4s")
+	game := newStudGame(t, players, deck, SevenStud)
+	game.Play()
+	// the others call the bring-in, then everyone checks four more streets
+	for seat, acts := range []int{5, 4, 5} {
+		if players[seat].acts != acts {
+			t.Fatalf("expected seat %d to act %d times but was %d", seat, acts, players[seat].acts)
+		}
+	}
+}
+
+func Test_can_play_razz(t *testing.T) {
+	// seat 0 completes the bring-in and makes a wheel
+	players := []*testPlayer{{chips: 1000, script: []Action{{Type: Raise, Amount: 100}}}, {chips: 1000}}
+	deck := stackedDeck(t, "Ac Kd 2c Qd 3c Jd 4c Td 5c 9d 9c 8d Kc 7d")
+	game := newStudGame(t, players, deck, Razz)
+	game.Play()
+	expectChips(t, players, 1110, 890)
+	if len(players[0].hole) != 7 || len(players[0].board) != 0 {
+		t.Fatalf("expected 7 cards but was %s", PrintHand(players[0].hole))
+	}
+}
+
+func Test_can_split_stud_hi_lo(t *testing.T) {
+	// seat 0 makes a six low and seat 1 two pair
+	players := []*testPlayer{{chips: 1000}, {chips: 1000}}
+	deck := stackedDeck(t, "Ac Kc 2d Kd 3h 7s 4s 7h 6c Qc 9d Qd Jh 8s")
+	game := newStudGame(t, players, deck, SevenStudHL)
+	game.Play()
+	if players[0].won != 35 || players[1].won != 35 {
+		t.Fatalf("expected pot of 70 to be split but was %d and %d", players[0].won, players[1].won)
+	}
+}
+
+func Test_stud_seats_only_players_the_deck_can_deal_to(t *testing.T) {
+	players := make([]*testPlayer, MaxStudSeats+1)
+	for i := range players {
+		players[i] = &testPlayer{chips: 1000}
+	}
+	if _, err := NewStudGame(gamePlayers(players), NewPokerDeck(), SevenStud, FixedLimit, NewBlinds(25, 100), 0); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+	// a short deck deals six cards each and a river to five players at most
+	if _, err := NewStudGame(gamePlayers(players[:6]), NewShortDeck(), SevenStud, FixedLimit, NewBlinds(25, 100), 0); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+	game := newStudGame(t, players[:5], NewShortDeck(), SevenStud)
+	game.Play()
+	if err := game.AddPlayer(players[5]); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+}
+
+func Test_river_is_shared_when_deck_runs_short(t *testing.T) {
+	players := make([]*testPlayer, MaxStudSeats)
+	for i := range players {
+		players[i] = &testPlayer{chips: 1000}
+	}
+	deck := NewPokerDeckWithSource(NewSeededSource(1))
+	game := newStudGame(t, players, deck, SevenStud)
+	game.Play()
+	var chips uint32
+	for i, p := range players {
+		if len(p.hole) != 6 || PrintHand(p.board) != PrintHand(game.board) || len(game.board) != 1 {
+			t.Fatalf("expected seat %d to have 6 cards and a shared river but was %s and %s",
+				i, PrintHand(p.hole), PrintHand(p.board))
+		}
+		chips += p.chips
+	}
+	if chips != 8000 {
+		t.Fatalf("expected 8000 chips on the table but was %d", chips)
+	}
+	if err := game.AddPlayer(&testPlayer{}); err != TableFull {
+		t.Fatalf("expected TableFull but was %v", err)
+	}
+}
+
+func newStudGame(t *testing.T, players []*testPlayer, deck Deck, variant GameType) *StudGame {
+	game, err := NewStudGame(gamePlayers(players), deck, variant, FixedLimit, NewBlinds(25, 100).WithAnte(10), 0)
+	if err != nil {
+		t.Fatalf("cannot create stud game: %v", err)
+	}
+	return game
+}
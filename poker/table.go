@@ -0,0 +1,418 @@
+// Copyright (c) Toby DiPasquale. See accompanying LICENSE file for
+// detailed licensing information.
+package poker
+
+import (
+	"fmt"
+)
+
+const MaxSeats = 10
+
+// Number of times a player is asked to act before an illegal action is
+// replaced by a check or a fold.
+const MaxActionAttempts = 3
+
+// Button rules
+const (
+	// The big blind moves to the next player in the hand every time and the
+	// small blind and button follow it onto the seats it left, even when
+	// those seats have emptied. Nobody gets to skip the big blind.
+	DeadButton = iota
+	// The button moves to the next player in the hand every time and the
+	// blinds follow it. Players can skip blinds when the seats before them
+	// empty.
+	MovingButton
+)
+
+var (
+	TableFull   = fmt.Errorf("table is full")
+	InvalidSeat = fmt.Errorf("no player in that seat")
+)
+
+// Seating, button position and forced bets shared by the games. The per-seat
+// slices are indexed by seat, and empty seats hold a nil player.
+type table struct {
+	players     []Player
+	deck        Deck
+	limit       GameLimit
+	blinds      Blinds
+	maxRaises   int
+	buttonRule  int
+	dealer      int
+	smallBlind  int
+	bigBlind    int
+	started     bool
+	sittingOut  []bool
+	missedSmall []bool
+	missedBig   []bool
+	hands       [][]Card
+	bets        []uint32
+	acted       []bool
+	folded      []bool
+	pots        *PotManager
+}
+
+// Create a table with the given players in seats from 0 up.
+func newTable(players []Player, deck Deck, limit GameLimit, blinds Blinds, maxRaises int) table {
+	tbl := table{
+		deck:       deck,
+		limit:      limit,
+		blinds:     blinds,
+		maxRaises:  maxRaises,
+		smallBlind: -1,
+		bigBlind:   -1,
+	}
+	for _, player := range players {
+		tbl.players[tbl.addSeat()] = player
+	}
+	tbl.pots = NewPotManager(len(players))
+	return tbl
+}
+
+// Add an empty seat to the table, returning its number.
+func (tbl *table) addSeat() int {
+	tbl.players = append(tbl.players, nil)
+	tbl.sittingOut = append(tbl.sittingOut, false)
+	tbl.missedSmall = append(tbl.missedSmall, false)
+	tbl.missedBig = append(tbl.missedBig, false)
+	tbl.hands = append(tbl.hands, nil)
+	tbl.bets = append(tbl.bets, 0)
+	tbl.acted = append(tbl.acted, false)
+	tbl.folded = append(tbl.folded, false)
+	return len(tbl.players) - 1
+}
+
+// ----- PUBLIC TABLE API ----------------------------------------------------
+
+// Add a player to the first empty seat. A player who joins once play has
+// started must post a big blind before being dealt in. Returns TableFull if
+// there are already MaxSeats players.
+func (tbl *table) AddPlayer(player Player) error {
+	seat := -1
+	for i, p := range tbl.players {
+		if p == nil {
+			seat = i
+			break
+		}
+	}
+	if seat < 0 {
+		if len(tbl.players) >= MaxSeats {
+			return TableFull
+		}
+		seat = tbl.addSeat()
+	}
+	tbl.players[seat] = player
+	tbl.sittingOut[seat] = false
+	tbl.missedSmall[seat] = false
+	tbl.missedBig[seat] = tbl.started
+	return nil
+}
+
+// Take the player in the given seat away from the table, leaving the seat
+// empty. Returns InvalidSeat if there is nobody in it.
+func (tbl *table) RemovePlayer(seat int) error {
+	if seat < 0 || seat >= len(tbl.players) || tbl.players[seat] == nil {
+		return InvalidSeat
+	}
+	tbl.players[seat] = nil
+	return nil
+}
+
+// Leave the player in the given seat out of the hands that follow, keeping
+// their seat. Blinds they would have posted while sitting out are owed when
+// they sit back in. Returns InvalidSeat if there is nobody in it.
+func (tbl *table) SitOut(seat int) error {
+	if seat < 0 || seat >= len(tbl.players) || tbl.players[seat] == nil {
+		return InvalidSeat
+	}
+	tbl.sittingOut[seat] = true
+	return nil
+}
+
+// Deal the player in the given seat back in from the next hand. Returns
+// InvalidSeat if there is nobody in it.
+func (tbl *table) SitIn(seat int) error {
+	if seat < 0 || seat >= len(tbl.players) || tbl.players[seat] == nil {
+		return InvalidSeat
+	}
+	tbl.sittingOut[seat] = false
+	return nil
+}
+
+// Choose how the button and blinds move when seats empty. (i.e. DeadButton
+// or MovingButton) Tables use DeadButton unless told otherwise.
+func (tbl *table) SetButtonRule(rule int) {
+	tbl.buttonRule = rule
+}
+
+// ----- TABLE FUNCTIONS -----------------------------------------------------
+
+// Can the player in the given seat be dealt into a hand?
+func (tbl *table) active(seat int) bool {
+	return tbl.players[seat] != nil && !tbl.sittingOut[seat] && tbl.players[seat].Chips() > 0
+}
+
+// Find the first seat after the given one whose player can be dealt in, or
+// -1 if there is none.
+func (tbl *table) nextActive(seat int) int {
+	cnt := len(tbl.players)
+	for j := 1; j <= cnt; j++ {
+		if p := ((seat+j)%cnt + cnt) % cnt; tbl.active(p) {
+			return p
+		}
+	}
+	return -1
+}
+
+// Shuffle up, clear the table for a new hand and move the button. Players
+// who cannot be dealt in are folded before the hand starts. Returns false if
+// there are not enough players for a hand.
+func (tbl *table) startHand() bool {
+	players := 0
+	for i := range tbl.players {
+		tbl.hands[i] = tbl.hands[i][:0]
+		tbl.bets[i] = 0
+		tbl.acted[i] = false
+		tbl.folded[i] = !tbl.active(i)
+		if !tbl.folded[i] {
+			players++
+		}
+	}
+	if players < 2 {
+		return false
+	}
+	tbl.deck.Shuffle()
+	tbl.pots = NewPotManager(len(tbl.players))
+	for i, folded := range tbl.folded {
+		if folded {
+			tbl.pots.Fold(i)
+		}
+	}
+	tbl.moveButton(players)
+	tbl.started = true
+	return true
+}
+
+// Move the button and blinds on for the next hand. With two players the
+// button posts the small blind. With more, the blinds follow the button
+// rule, and anyone sitting out whose turn for a blind is skipped owes it.
+func (tbl *table) moveButton(players int) {
+	prevSmall, prevBig := tbl.smallBlind, tbl.bigBlind
+	switch {
+	case players == 2:
+		tbl.dealer = tbl.nextActive(tbl.dealer)
+		tbl.smallBlind = tbl.dealer
+		tbl.bigBlind = tbl.nextActive(tbl.dealer)
+		return
+	case !tbl.started || prevBig < 0 || tbl.buttonRule == MovingButton:
+		tbl.dealer = tbl.nextActive(tbl.dealer)
+		tbl.smallBlind = tbl.nextActive(tbl.dealer)
+		tbl.bigBlind = tbl.nextActive(tbl.smallBlind)
+	default:
+		tbl.bigBlind = tbl.nextActive(prevBig)
+		tbl.smallBlind = prevBig
+		tbl.dealer = prevSmall
+	}
+	if !tbl.started || prevBig < 0 {
+		return
+	}
+	tbl.markMissed(prevBig, tbl.bigBlind, true)
+	if prevSmall >= 0 && tbl.smallBlind >= 0 {
+		tbl.markMissed(prevSmall, tbl.smallBlind, false)
+		if tbl.players[tbl.smallBlind] != nil && !tbl.active(tbl.smallBlind) {
+			tbl.missedSmall[tbl.smallBlind] = true
+		}
+	}
+}
+
+// Record a missed blind for every player sitting out in the seats strictly
+// between from and to. Missing the big blind means missing the small blind
+// that would have followed it too.
+func (tbl *table) markMissed(from, to int, big bool) {
+	cnt := len(tbl.players)
+	for p := (from + 1) % cnt; p != to && p != from; p = (p + 1) % cnt {
+		if tbl.players[p] == nil || tbl.active(p) {
+			continue
+		}
+		tbl.missedSmall[p] = true
+		if big {
+			tbl.missedBig[p] = true
+		}
+	}
+}
+
+// Collect the antes and blinds for the hand, along with any blinds owed by
+// players coming back into the game. Owed big blinds are live, counting
+// towards the player's bet; antes and owed small blinds are dead.
+func (tbl *table) postBlinds() {
+	if tbl.blinds.bigBlindAnte {
+		tbl.post(tbl.bigBlind, tbl.blinds.ante, false)
+	} else {
+		tbl.postAntes()
+	}
+	if tbl.smallBlind >= 0 && !tbl.folded[tbl.smallBlind] {
+		tbl.post(tbl.smallBlind, tbl.blinds.small, true)
+	}
+	tbl.post(tbl.bigBlind, tbl.blinds.big, true)
+	for p := range tbl.players {
+		if tbl.folded[p] {
+			continue
+		}
+		// posting either blind in turn settles what is owed
+		if p != tbl.smallBlind && p != tbl.bigBlind {
+			if tbl.missedBig[p] {
+				tbl.post(p, tbl.blinds.big, true)
+			}
+			if tbl.missedSmall[p] {
+				tbl.post(p, tbl.blinds.small, false)
+			}
+		}
+		tbl.missedSmall[p] = false
+		tbl.missedBig[p] = false
+	}
+}
+
+// Collect a dead ante from every player in the hand.
+func (tbl *table) postAntes() {
+	if tbl.blinds.ante == 0 {
+		return
+	}
+	for p := range tbl.players {
+		if !tbl.folded[p] {
+			tbl.post(p, tbl.blinds.ante, false)
+		}
+	}
+}
+
+// Take a forced bet from the given seat, or all their chips if they have
+// less. Live bets count towards what the player has to call; dead ones go
+// straight into the pot.
+func (tbl *table) post(seat int, amount uint32, live bool) {
+	if chips := tbl.players[seat].Chips(); amount > chips {
+		amount = chips
+	}
+	if amount == 0 {
+		return
+	}
+	tbl.players[seat].Pay(amount)
+	if live {
+		tbl.bets[seat] += amount
+	} else {
+		tbl.pots.Contribute(seat, amount)
+	}
+}
+
+// Deal the given number of cards to each player in the hand privately,
+// starting to the left of the button and ending with the button.
+func (tbl *table) dealPrivate(cards int) {
+	cnt := len(tbl.players)
+	for i := 0; i < cards; i++ {
+		for j := 1; j <= cnt; j++ {
+			p := (tbl.dealer + j) % cnt
+			if tbl.folded[p] {
+				continue
+			}
+			card := tbl.deck.MustDeal()
+			tbl.hands[p] = append(tbl.hands[p], card)
+			tbl.players[p].DealPrivate(card)
+		}
+	}
+}
+
+// Report the bet size for the early betting rounds: the big blind.
+func (tbl *table) smallBet() uint32 {
+	return tbl.blinds.big
+}
+
+// Report the bet size for the later betting rounds. Limit games double the
+// bet size here; other games keep the big blind as the minimum bet.
+func (tbl *table) bigBet() uint32 {
+	if tbl.limit == FixedLimit {
+		return 2 * tbl.blinds.big
+	}
+	return tbl.blinds.big
+}
+
+// Report the seat that acts first in the opening round of betting: the
+// player to the left of the big blind. Heads up, this is the button.
+func (tbl *table) firstPreflop() int {
+	return (tbl.bigBlind + 1) % len(tbl.players)
+}
+
+// Report the seat that acts first in the later rounds of betting: the player
+// to the left of the button.
+func (tbl *table) firstPostflop() int {
+	return (tbl.dealer + 1) % len(tbl.players)
+}
+
+// Run a round of betting with the given bet size, starting with the given
+// seat. Seats marked as having acted, such as the bring-in, are only asked to
+// act again if someone raises. A player who attempts an illegal action is
+// asked again, and can find out why from the round's Rejected, up to
+// MaxActionAttempts times in all; after that they check if they can and fold
+// otherwise. At the end of the round everything bet goes into the pots.
+func (tbl *table) betting(betSize uint32, first int) {
+	seats := make([]Seat, len(tbl.players))
+	for i, player := range tbl.players {
+		seats[i].Folded = tbl.folded[i]
+		if !tbl.folded[i] {
+			seats[i].Stack = player.Chips()
+			seats[i].Bet = tbl.bets[i]
+			seats[i].Acted = tbl.acted[i]
+		}
+	}
+	round := NewBettingRound(tbl.limit, betSize, tbl.maxRaises, tbl.pots.Total(), seats, first)
+	for !round.Done() {
+		seat := round.Actor()
+		before := round.BetOf(seat)
+		var err error
+		for attempt := 0; attempt < MaxActionAttempts; attempt++ {
+			if err = round.Act(seat, tbl.players[seat].Act(round)); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			passive := Action{Type: Fold}
+			if round.ToCall(seat) == 0 {
+				passive.Type = Check
+			}
+			round.Act(seat, passive)
+		}
+		if paid := round.BetOf(seat) - before; paid > 0 {
+			tbl.players[seat].Pay(paid)
+		}
+		tbl.folded[seat] = round.Folded(seat)
+	}
+	for i := range tbl.bets {
+		tbl.pots.Contribute(i, round.BetOf(i))
+		if round.Folded(i) {
+			tbl.pots.Fold(i)
+		}
+		tbl.bets[i] = 0
+		tbl.acted[i] = false
+	}
+}
+
+// Report how many players have not folded.
+func (tbl *table) live() int {
+	n := 0
+	for _, folded := range tbl.folded {
+		if !folded {
+			n++
+		}
+	}
+	return n
+}
+
+// Award the main pot and any side pots to the best hand(s) eligible for
+// each, with contenders indexed by seat, and empty the pots. Odd chips go to
+// the first winner to the left of the button.
+func (tbl *table) award(contenders []Contender, hiLo bool) {
+	won := tbl.pots.Award(contenders, hiLo, (tbl.dealer+1)%len(tbl.players))
+	for p, amount := range won {
+		if amount > 0 {
+			tbl.players[p].Win(amount)
+		}
+	}
+	tbl.pots.Reset()
+}